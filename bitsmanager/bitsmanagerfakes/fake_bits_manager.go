@@ -2,15 +2,17 @@
 package bitsmanagerfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
 )
 
 type FakeBitsManager struct {
-	UploadStub        func(appGuid string, path string) error
+	UploadStub        func(ctx context.Context, appGuid string, path string) error
 	uploadMutex       sync.RWMutex
 	uploadArgsForCall []struct {
+		ctx     context.Context
 		appGuid string
 		path    string
 	}
@@ -32,9 +34,10 @@ type FakeBitsManager struct {
 	copyBitsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetSha1Stub        func(path string) (sha1 string, err error)
+	GetSha1Stub        func(ctx context.Context, path string) (sha1 string, err error)
 	getSha1Mutex       sync.RWMutex
 	getSha1ArgsForCall []struct {
+		ctx  context.Context
 		path string
 	}
 	getSha1Returns struct {
@@ -45,9 +48,10 @@ type FakeBitsManager struct {
 		result1 string
 		result2 error
 	}
-	IsDiffStub        func(path string, currentSha1 string) (isDiff bool, sha1 string, err error)
+	IsDiffStub        func(ctx context.Context, path string, currentSha1 string) (isDiff bool, sha1 string, err error)
 	isDiffMutex       sync.RWMutex
 	isDiffArgsForCall []struct {
+		ctx         context.Context
 		path        string
 		currentSha1 string
 	}
@@ -65,17 +69,18 @@ type FakeBitsManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBitsManager) Upload(appGuid string, path string) error {
+func (fake *FakeBitsManager) Upload(ctx context.Context, appGuid string, path string) error {
 	fake.uploadMutex.Lock()
 	ret, specificReturn := fake.uploadReturnsOnCall[len(fake.uploadArgsForCall)]
 	fake.uploadArgsForCall = append(fake.uploadArgsForCall, struct {
+		ctx     context.Context
 		appGuid string
 		path    string
-	}{appGuid, path})
-	fake.recordInvocation("Upload", []interface{}{appGuid, path})
+	}{ctx, appGuid, path})
+	fake.recordInvocation("Upload", []interface{}{ctx, appGuid, path})
 	fake.uploadMutex.Unlock()
 	if fake.UploadStub != nil {
-		return fake.UploadStub(appGuid, path)
+		return fake.UploadStub(ctx, appGuid, path)
 	}
 	if specificReturn {
 		return ret.result1
@@ -89,10 +94,10 @@ func (fake *FakeBitsManager) UploadCallCount() int {
 	return len(fake.uploadArgsForCall)
 }
 
-func (fake *FakeBitsManager) UploadArgsForCall(i int) (string, string) {
+func (fake *FakeBitsManager) UploadArgsForCall(i int) (context.Context, string, string) {
 	fake.uploadMutex.RLock()
 	defer fake.uploadMutex.RUnlock()
-	return fake.uploadArgsForCall[i].appGuid, fake.uploadArgsForCall[i].path
+	return fake.uploadArgsForCall[i].ctx, fake.uploadArgsForCall[i].appGuid, fake.uploadArgsForCall[i].path
 }
 
 func (fake *FakeBitsManager) UploadReturns(result1 error) {
@@ -163,16 +168,17 @@ func (fake *FakeBitsManager) CopyBitsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeBitsManager) GetSha1(path string) (sha1 string, err error) {
+func (fake *FakeBitsManager) GetSha1(ctx context.Context, path string) (sha1 string, err error) {
 	fake.getSha1Mutex.Lock()
 	ret, specificReturn := fake.getSha1ReturnsOnCall[len(fake.getSha1ArgsForCall)]
 	fake.getSha1ArgsForCall = append(fake.getSha1ArgsForCall, struct {
+		ctx  context.Context
 		path string
-	}{path})
-	fake.recordInvocation("GetSha1", []interface{}{path})
+	}{ctx, path})
+	fake.recordInvocation("GetSha1", []interface{}{ctx, path})
 	fake.getSha1Mutex.Unlock()
 	if fake.GetSha1Stub != nil {
-		return fake.GetSha1Stub(path)
+		return fake.GetSha1Stub(ctx, path)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -186,10 +192,10 @@ func (fake *FakeBitsManager) GetSha1CallCount() int {
 	return len(fake.getSha1ArgsForCall)
 }
 
-func (fake *FakeBitsManager) GetSha1ArgsForCall(i int) string {
+func (fake *FakeBitsManager) GetSha1ArgsForCall(i int) (context.Context, string) {
 	fake.getSha1Mutex.RLock()
 	defer fake.getSha1Mutex.RUnlock()
-	return fake.getSha1ArgsForCall[i].path
+	return fake.getSha1ArgsForCall[i].ctx, fake.getSha1ArgsForCall[i].path
 }
 
 func (fake *FakeBitsManager) GetSha1Returns(result1 string, result2 error) {
@@ -214,17 +220,18 @@ func (fake *FakeBitsManager) GetSha1ReturnsOnCall(i int, result1 string, result2
 	}{result1, result2}
 }
 
-func (fake *FakeBitsManager) IsDiff(path string, currentSha1 string) (isDiff bool, sha1 string, err error) {
+func (fake *FakeBitsManager) IsDiff(ctx context.Context, path string, currentSha1 string) (isDiff bool, sha1 string, err error) {
 	fake.isDiffMutex.Lock()
 	ret, specificReturn := fake.isDiffReturnsOnCall[len(fake.isDiffArgsForCall)]
 	fake.isDiffArgsForCall = append(fake.isDiffArgsForCall, struct {
+		ctx         context.Context
 		path        string
 		currentSha1 string
-	}{path, currentSha1})
-	fake.recordInvocation("IsDiff", []interface{}{path, currentSha1})
+	}{ctx, path, currentSha1})
+	fake.recordInvocation("IsDiff", []interface{}{ctx, path, currentSha1})
 	fake.isDiffMutex.Unlock()
 	if fake.IsDiffStub != nil {
-		return fake.IsDiffStub(path, currentSha1)
+		return fake.IsDiffStub(ctx, path, currentSha1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -238,10 +245,10 @@ func (fake *FakeBitsManager) IsDiffCallCount() int {
 	return len(fake.isDiffArgsForCall)
 }
 
-func (fake *FakeBitsManager) IsDiffArgsForCall(i int) (string, string) {
+func (fake *FakeBitsManager) IsDiffArgsForCall(i int) (context.Context, string, string) {
 	fake.isDiffMutex.RLock()
 	defer fake.isDiffMutex.RUnlock()
-	return fake.isDiffArgsForCall[i].path, fake.isDiffArgsForCall[i].currentSha1
+	return fake.isDiffArgsForCall[i].ctx, fake.isDiffArgsForCall[i].path, fake.isDiffArgsForCall[i].currentSha1
 }
 
 func (fake *FakeBitsManager) IsDiffReturns(result1 bool, result2 string, result3 error) {
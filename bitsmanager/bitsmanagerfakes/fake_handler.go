@@ -2,15 +2,17 @@
 package bitsmanagerfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
 )
 
 type FakeHandler struct {
-	GetZipFileStub        func(path string) (fileHandler bitsmanager.FileHandler, err error)
+	GetZipFileStub        func(ctx context.Context, path string) (fileHandler bitsmanager.FileHandler, err error)
 	getZipFileMutex       sync.RWMutex
 	getZipFileArgsForCall []struct {
+		ctx  context.Context
 		path string
 	}
 	getZipFileReturns struct {
@@ -21,9 +23,10 @@ type FakeHandler struct {
 		result1 bitsmanager.FileHandler
 		result2 error
 	}
-	GetSha1FileStub        func(path string) (sha1 string, err error)
+	GetSha1FileStub        func(ctx context.Context, path string) (sha1 string, err error)
 	getSha1FileMutex       sync.RWMutex
 	getSha1FileArgsForCall []struct {
+		ctx  context.Context
 		path string
 	}
 	getSha1FileReturns struct {
@@ -49,16 +52,17 @@ type FakeHandler struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeHandler) GetZipFile(path string) (fileHandler bitsmanager.FileHandler, err error) {
+func (fake *FakeHandler) GetZipFile(ctx context.Context, path string) (fileHandler bitsmanager.FileHandler, err error) {
 	fake.getZipFileMutex.Lock()
 	ret, specificReturn := fake.getZipFileReturnsOnCall[len(fake.getZipFileArgsForCall)]
 	fake.getZipFileArgsForCall = append(fake.getZipFileArgsForCall, struct {
+		ctx  context.Context
 		path string
-	}{path})
-	fake.recordInvocation("GetZipFile", []interface{}{path})
+	}{ctx, path})
+	fake.recordInvocation("GetZipFile", []interface{}{ctx, path})
 	fake.getZipFileMutex.Unlock()
 	if fake.GetZipFileStub != nil {
-		return fake.GetZipFileStub(path)
+		return fake.GetZipFileStub(ctx, path)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -72,10 +76,10 @@ func (fake *FakeHandler) GetZipFileCallCount() int {
 	return len(fake.getZipFileArgsForCall)
 }
 
-func (fake *FakeHandler) GetZipFileArgsForCall(i int) string {
+func (fake *FakeHandler) GetZipFileArgsForCall(i int) (context.Context, string) {
 	fake.getZipFileMutex.RLock()
 	defer fake.getZipFileMutex.RUnlock()
-	return fake.getZipFileArgsForCall[i].path
+	return fake.getZipFileArgsForCall[i].ctx, fake.getZipFileArgsForCall[i].path
 }
 
 func (fake *FakeHandler) GetZipFileReturns(result1 bitsmanager.FileHandler, result2 error) {
@@ -100,16 +104,17 @@ func (fake *FakeHandler) GetZipFileReturnsOnCall(i int, result1 bitsmanager.File
 	}{result1, result2}
 }
 
-func (fake *FakeHandler) GetSha1File(path string) (sha1 string, err error) {
+func (fake *FakeHandler) GetSha1File(ctx context.Context, path string) (sha1 string, err error) {
 	fake.getSha1FileMutex.Lock()
 	ret, specificReturn := fake.getSha1FileReturnsOnCall[len(fake.getSha1FileArgsForCall)]
 	fake.getSha1FileArgsForCall = append(fake.getSha1FileArgsForCall, struct {
+		ctx  context.Context
 		path string
-	}{path})
-	fake.recordInvocation("GetSha1File", []interface{}{path})
+	}{ctx, path})
+	fake.recordInvocation("GetSha1File", []interface{}{ctx, path})
 	fake.getSha1FileMutex.Unlock()
 	if fake.GetSha1FileStub != nil {
-		return fake.GetSha1FileStub(path)
+		return fake.GetSha1FileStub(ctx, path)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -123,10 +128,10 @@ func (fake *FakeHandler) GetSha1FileCallCount() int {
 	return len(fake.getSha1FileArgsForCall)
 }
 
-func (fake *FakeHandler) GetSha1FileArgsForCall(i int) string {
+func (fake *FakeHandler) GetSha1FileArgsForCall(i int) (context.Context, string) {
 	fake.getSha1FileMutex.RLock()
 	defer fake.getSha1FileMutex.RUnlock()
-	return fake.getSha1FileArgsForCall[i].path
+	return fake.getSha1FileArgsForCall[i].ctx, fake.getSha1FileArgsForCall[i].path
 }
 
 func (fake *FakeHandler) GetSha1FileReturns(result1 string, result2 error) {
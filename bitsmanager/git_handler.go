@@ -1,6 +1,7 @@
 package bitsmanager
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
@@ -17,30 +18,36 @@ import (
 type GitHandler struct {
 }
 
-func NewGitHandler(skipInsecureSSL bool) *GitHandler {
+func NewGitHandler(tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) *GitHandler {
 	customClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipInsecureSSL},
+			Proxy:           proxy,
+			TLSClientConfig: tlsConfig,
 		},
 	}
 	client.InstallProtocol(
 		"https",
 		githttp.NewClient(customClient),
 	)
+	client.InstallProtocol(
+		"http",
+		githttp.NewClient(customClient),
+	)
 	return &GitHandler{}
 }
-func (h GitHandler) GetZipFile(path string) (FileHandler, error) {
+func (h GitHandler) GetZipFile(ctx context.Context, path string) (FileHandler, error) {
 	tmpDir, err := ioutil.TempDir("", "git-tf")
 	if err != nil {
 		return FileHandler{}, err
 	}
 	gitUtils := h.makeGitUtils(tmpDir, path)
-	err = gitUtils.Clone()
+	err = gitUtils.Clone(ctx)
 	if err != nil {
+		os.RemoveAll(tmpDir)
 		return FileHandler{}, err
 	}
 	err = os.RemoveAll(filepath.Join(tmpDir, ".git"))
-	localFh, err := NewLocalHandler().GetZipFile(tmpDir)
+	localFh, err := NewLocalHandler().GetZipFile(ctx, tmpDir)
 	if err != nil {
 		return FileHandler{}, err
 	}
@@ -77,14 +84,14 @@ func (h GitHandler) makeGitUtils(tmpDir, path string) *GitUtils {
 	}
 	return gitUtils
 }
-func (h GitHandler) GetSha1File(path string) (string, error) {
+func (h GitHandler) GetSha1File(ctx context.Context, path string) (string, error) {
 	tmpDir, err := ioutil.TempDir("", "git-tf")
 	if err != nil {
 		return "", err
 	}
 	defer os.RemoveAll(tmpDir)
 	gitUtils := h.makeGitUtils(tmpDir, path)
-	return gitUtils.GetCommitSha1()
+	return gitUtils.GetCommitSha1(ctx)
 }
 func (h GitHandler) Detect(path string) bool {
 	if !common.IsWebURL(path) {
@@ -1,6 +1,7 @@
 package bitsmanager
 
 import (
+	"context"
 	"fmt"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -18,18 +19,18 @@ type GitUtils struct {
 
 var refTypes []string = []string{"heads", "tags"}
 
-func (g GitUtils) Clone() error {
-	_, err := g.findRepo(false)
+func (g GitUtils) Clone(ctx context.Context) error {
+	_, err := g.findRepo(ctx, false)
 	if err != nil {
 		return err
 	}
 	return nil
 }
-func (g GitUtils) GetCommitSha1() (string, error) {
+func (g GitUtils) GetCommitSha1(ctx context.Context) (string, error) {
 	if g.refNameIsHash() {
 		return g.RefName, nil
 	}
-	repo, err := g.findRepo(true)
+	repo, err := g.findRepo(ctx, true)
 	if err != nil {
 		return "", err
 	}
@@ -47,8 +48,8 @@ func (g GitUtils) GetCommitSha1() (string, error) {
 func (g GitUtils) refNameIsHash() bool {
 	return len(g.RefName) == 40
 }
-func (g GitUtils) findRepoFromHash(isBare bool) (*git.Repository, error) {
-	repo, err := git.PlainClone(g.Folder, isBare, &git.CloneOptions{
+func (g GitUtils) findRepoFromHash(ctx context.Context, isBare bool) (*git.Repository, error) {
+	repo, err := git.PlainCloneContext(ctx, g.Folder, isBare, &git.CloneOptions{
 		URL:  g.Url,
 		Auth: g.AuthMethod,
 	})
@@ -68,13 +69,13 @@ func (g GitUtils) findRepoFromHash(isBare bool) (*git.Repository, error) {
 	}
 	return repo, nil
 }
-func (g GitUtils) findRepo(isBare bool) (repo *git.Repository, err error) {
+func (g GitUtils) findRepo(ctx context.Context, isBare bool) (repo *git.Repository, err error) {
 	if g.refNameIsHash() {
-		repo, err = g.findRepoFromHash(isBare)
+		repo, err = g.findRepoFromHash(ctx, isBare)
 		return
 	}
 	for _, refType := range refTypes {
-		repo, err = git.PlainClone(g.Folder, isBare, &git.CloneOptions{
+		repo, err = git.PlainCloneContext(ctx, g.Folder, isBare, &git.CloneOptions{
 			URL:          g.Url,
 			SingleBranch: true,
 			Auth:         g.AuthMethod,
@@ -4,29 +4,36 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 )
 
 type HttpHandler struct {
-	SkipInsecureSSL bool
+	TLSConfig *tls.Config
+	Proxy     func(*http.Request) (*url.URL, error)
 }
 
-func NewHttpHandler(skipInsecureSSL bool) *HttpHandler {
-	return &HttpHandler{skipInsecureSSL}
+func NewHttpHandler(tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) *HttpHandler {
+	return &HttpHandler{tlsConfig, proxy}
 }
-func (h HttpHandler) GetZipFile(path string) (FileHandler, error) {
+func (h HttpHandler) GetZipFile(ctx context.Context, path string) (FileHandler, error) {
 	client := h.makeHttpClient()
 	cleanFunc := func() error {
 		return nil
 	}
-	resp, err := client.Get(path)
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return FileHandler{}, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return FileHandler{}, err
 	}
@@ -70,8 +77,8 @@ func (h HttpHandler) Detect(path string) bool {
 }
 func (h HttpHandler) makeHttpClient() *http.Client {
 	tr := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: h.SkipInsecureSSL},
+		Proxy:           h.Proxy,
+		TLSClientConfig: h.TLSConfig,
 	}
 	return &http.Client{
 		Transport: tr,
@@ -154,13 +161,13 @@ func (h HttpHandler) writeTarToZip(r io.Reader, zipFile *os.File) error {
 	}
 	return nil
 }
-func (h HttpHandler) GetSha1File(path string) (string, error) {
+func (h HttpHandler) GetSha1File(ctx context.Context, path string) (string, error) {
 	client := h.makeHttpClient()
 	req, err := http.NewRequest("GET", path, nil)
 	if err != nil {
 		return "", err
 	}
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return "", err
 	}
@@ -2,6 +2,7 @@ package bitsmanager
 
 import (
 	"code.cloudfoundry.org/cli/cf/appfiles"
+	"context"
 	"io/ioutil"
 	"os"
 )
@@ -12,7 +13,10 @@ type LocalHandler struct {
 func NewLocalHandler() *LocalHandler {
 	return &LocalHandler{}
 }
-func (h LocalHandler) GetZipFile(path string) (FileHandler, error) {
+func (h LocalHandler) GetZipFile(ctx context.Context, path string) (FileHandler, error) {
+	if err := ctx.Err(); err != nil {
+		return FileHandler{}, err
+	}
 	zipFile, err := ioutil.TempFile("", "uploads-tf")
 	if err != nil {
 		return FileHandler{}, err
@@ -42,8 +46,8 @@ func (h LocalHandler) Detect(path string) bool {
 	}
 	return true
 }
-func (h LocalHandler) GetSha1File(path string) (string, error) {
-	fileHandler, err := h.GetZipFile(path)
+func (h LocalHandler) GetSha1File(ctx context.Context, path string) (string, error) {
+	fileHandler, err := h.GetZipFile(ctx, path)
 	if err != nil {
 		return "", err
 	}
@@ -1,7 +1,10 @@
 package bitsmanager
 
 import (
+	"code.cloudfoundry.org/cli/cf/errors"
+	"context"
 	"fmt"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 	"io"
 )
 
@@ -11,8 +14,8 @@ const (
 )
 
 type Handler interface {
-	GetZipFile(path string) (fileHandler FileHandler, err error)
-	GetSha1File(path string) (sha1 string, err error)
+	GetZipFile(ctx context.Context, path string) (fileHandler FileHandler, err error)
+	GetSha1File(ctx context.Context, path string) (sha1 string, err error)
 	Detect(path string) bool
 }
 type FileHandler struct {
@@ -21,51 +24,70 @@ type FileHandler struct {
 	Clean   func() error
 }
 type BitsManager interface {
-	Upload(appGuid string, path string) error
+	Upload(ctx context.Context, appGuid string, path string) error
 	CopyBits(origAppGuid string, newAppGuid string) error
-	GetSha1(path string) (sha1 string, err error)
-	IsDiff(path string, currentSha1 string) (isDiff bool, sha1 string, err error)
+	GetSha1(ctx context.Context, path string) (sha1 string, err error)
+	IsDiff(ctx context.Context, path string, currentSha1 string) (isDiff bool, sha1 string, err error)
 }
 
 type CloudControllerBitsManager struct {
 	appBitsRepo ApplicationBitsRepository
 	handlers    []Handler
+	retryPolicy common.RetryPolicy
 }
 
-func NewCloudControllerBitsManager(appBitsRepo ApplicationBitsRepository, handlers []Handler) (manager CloudControllerBitsManager) {
+func NewCloudControllerBitsManager(appBitsRepo ApplicationBitsRepository, handlers []Handler, retryPolicy common.RetryPolicy) (manager CloudControllerBitsManager) {
 	manager.appBitsRepo = appBitsRepo
 	manager.handlers = handlers
+	manager.retryPolicy = retryPolicy
 	return
 }
-func (m CloudControllerBitsManager) GetSha1(path string) (string, error) {
+func (m CloudControllerBitsManager) GetSha1(ctx context.Context, path string) (string, error) {
 	h, err := m.chooseHandler(path)
 	if err != nil {
 		return "", err
 	}
-	return h.GetSha1File(path)
+	return h.GetSha1File(ctx, path)
 }
 func (m CloudControllerBitsManager) CopyBits(origAppGuid string, newAppGuid string) error {
 	return m.appBitsRepo.CopyBits(origAppGuid, newAppGuid)
 }
-func (m CloudControllerBitsManager) Upload(appGuid string, path string) error {
+func (m CloudControllerBitsManager) Upload(ctx context.Context, appGuid string, path string) error {
 	h, err := m.chooseHandler(path)
 	if err != nil {
 		return err
 	}
-	fileHandler, err := h.GetZipFile(path)
-	if err != nil {
-		return err
+	// bits are streamed, zip file must be recreated on each attempt
+	return m.retryPolicy.Retry(ctx, func() (bool, error) {
+		fileHandler, err := h.GetZipFile(ctx, path)
+		if err != nil {
+			return false, err
+		}
+		defer fileHandler.ZipFile.Close()
+		defer fileHandler.Clean()
+		err = m.appBitsRepo.UploadBits(appGuid, fileHandler.ZipFile, fileHandler.Size)
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return m.isRetryableUploadErr(err), err
+	})
+}
+func (m CloudControllerBitsManager) isRetryableUploadErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if httpErr, ok := err.(errors.HTTPError); ok {
+		return m.retryPolicy.IsRetryableStatus(httpErr.StatusCode())
 	}
-	defer fileHandler.ZipFile.Close()
-	defer fileHandler.Clean()
-	return m.appBitsRepo.UploadBits(appGuid, fileHandler.ZipFile, fileHandler.Size)
+	// upload failed before cloud controller answered (e.g.: connection reset)
+	return true
 }
-func (m CloudControllerBitsManager) IsDiff(path string, currentSha1 string) (bool, string, error) {
+func (m CloudControllerBitsManager) IsDiff(ctx context.Context, path string, currentSha1 string) (bool, string, error) {
 	h, err := m.chooseHandler(path)
 	if err != nil {
 		return true, "", err
 	}
-	sha1Given, err := h.GetSha1File(path)
+	sha1Given, err := h.GetSha1File(ctx, path)
 	if err != nil {
 		return true, "", err
 	}
@@ -0,0 +1,103 @@
+package bitsmanager_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
+
+	"bytes"
+	"code.cloudfoundry.org/cli/cf/i18n"
+	"code.cloudfoundry.org/cli/cf/net"
+	"code.cloudfoundry.org/cli/cf/terminal"
+	"code.cloudfoundry.org/cli/cf/trace"
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager/bitsmanagerfakes"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+var _ = Describe("CloudControllerBitsManager", func() {
+	Describe("Upload", func() {
+		var server *httptest.Server
+		var mutex sync.Mutex
+		var connectionReset bool
+		var received int64
+		var manager CloudControllerBitsManager
+		var handler *bitsmanagerfakes.FakeHandler
+		zipContent := bytes.Repeat([]byte("0123456789abcdef"), 256*1024)
+		BeforeEach(func() {
+			connectionReset = false
+			received = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/v2/apps/app-guid/bits" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				mutex.Lock()
+				reset := !connectionReset
+				connectionReset = true
+				mutex.Unlock()
+				if reset {
+					// connection is reset while bits are sent
+					r.Body.Read(make([]byte, 1024))
+					conn, _, err := w.(http.Hijacker).Hijack()
+					Expect(err).ToNot(HaveOccurred())
+					conn.Close()
+					return
+				}
+				n, err := io.Copy(ioutil.Discard, r.Body)
+				if err != nil {
+					// cf cli gateway sends again a body already closed after a failed request
+					return
+				}
+				mutex.Lock()
+				received = n
+				mutex.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"metadata": {"guid": "app-guid"}}`))
+			}))
+			repository := cf_client.NewTerraformRepository("terraform-provider-cloudfoundry", "test", false)
+			repository.SetAPIEndpoint(server.URL)
+			repository.SetAccessToken("bearer token")
+			// gateway translates and colors what it logs
+			i18n.T = i18n.Init(repository)
+			terminal.InitColorSupport()
+			logger := trace.NewLogger(ioutil.Discard, false, "", "")
+			ui := terminal.NewUI(ioutil.NopCloser(nil), ioutil.Discard, terminal.NewTeePrinter(ioutil.Discard), logger)
+			gateway := net.NewCloudControllerGateway(repository, time.Now, ui, logger, "5")
+			appBitsRepo := NewCloudControllerApplicationBitsRepository(repository, gateway, http.DefaultClient)
+
+			handler = new(bitsmanagerfakes.FakeHandler)
+			handler.DetectReturns(true)
+			handler.GetZipFileStub = func(ctx context.Context, path string) (FileHandler, error) {
+				return FileHandler{
+					ZipFile: ioutil.NopCloser(bytes.NewReader(zipContent)),
+					Size:    int64(len(zipContent)),
+					Clean:   func() error { return nil },
+				}, nil
+			}
+			manager = NewCloudControllerBitsManager(appBitsRepo, []Handler{handler}, common.RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Millisecond,
+			})
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		It("should upload bits again with a new zip file when connection is reset during an attempt", func() {
+			err := manager.Upload(context.Background(), "app-guid", "/path/to/app")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(connectionReset).To(BeTrue())
+			Expect(handler.GetZipFileCallCount()).To(Equal(2))
+			Expect(received).To(BeNumerically(">", len(zipContent)))
+		})
+	})
+})
@@ -6,7 +6,6 @@ import (
 	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
 	. "code.cloudfoundry.org/cli/cf/i18n"
 	"code.cloudfoundry.org/cli/cf/net"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/cloudfoundry/gofileutils/fileutils"
@@ -50,13 +49,15 @@ type ApplicationBitsRepository interface {
 }
 
 type CloudControllerApplicationBitsRepository struct {
-	config  coreconfig.Reader
-	gateway net.Gateway
+	config     coreconfig.Reader
+	gateway    net.Gateway
+	httpClient *http.Client
 }
 
-func NewCloudControllerApplicationBitsRepository(config coreconfig.Reader, gateway net.Gateway) (repo CloudControllerApplicationBitsRepository) {
+func NewCloudControllerApplicationBitsRepository(config coreconfig.Reader, gateway net.Gateway, httpClient *http.Client) (repo CloudControllerApplicationBitsRepository) {
 	repo.config = config
 	repo.gateway = gateway
+	repo.httpClient = httpClient
 	return
 }
 func (repo CloudControllerApplicationBitsRepository) IsDiff(appGUID string, currentSha1 string) (bool, string, error) {
@@ -81,16 +82,7 @@ func (repo CloudControllerApplicationBitsRepository) GetApplicationSha1(appGUID
 	request.Header.Set("content-type", "application/json")
 	request.Header.Set("User-Agent", "go-cli "+repo.config.CLIVersion()+" / "+runtime.GOOS)
 
-	tr := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: repo.config.IsSSLDisabled()},
-	}
-	client := &http.Client{
-		Transport: tr,
-		Timeout:   2 * time.Second,
-	}
-
-	resp, err := client.Do(request)
+	resp, err := repo.httpClient.Do(request)
 	if err != nil {
 		return "", err
 	}
@@ -229,19 +221,21 @@ func createZipPartWriter(fileSize int64, writer *multipart.Writer) (io.Writer, e
 func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string, zipFile io.ReadCloser, fileSize int64) error {
 	apiURL := fmt.Sprintf("/v2/apps/%s/bits", appGUID)
 	r, w := io.Pipe()
+	// reading side is closed when request ends, writing zip stops with an error instead of blocking
+	defer r.Close()
 	mpw := multipart.NewWriter(w)
 	go func() {
-		var err error
-		defer w.Close()
+		// a failed write (e.g.: request body closed by transport after a connection reset)
+		// is given to request reading the pipe
 		part, err := mpw.CreateFormField("resources")
 		if err != nil {
-			mpw.Close()
-			panic(err)
+			w.CloseWithError(err)
+			return
 		}
 		_, err = io.Copy(part, bytes.NewBuffer([]byte("[]")))
 		if err != nil {
-			mpw.Close()
-			panic(err)
+			w.CloseWithError(err)
+			return
 		}
 		h := make(textproto.MIMEHeader)
 		h.Set("Content-Disposition", `form-data; name="application"; filename="application.zip"`)
@@ -251,14 +245,14 @@ func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string,
 
 		part, err = mpw.CreatePart(h)
 		if err != nil {
-			mpw.Close()
-			panic(err)
+			w.CloseWithError(err)
+			return
 		}
 		if _, err = io.Copy(part, zipFile); err != nil {
-			mpw.Close()
-			panic(err)
+			w.CloseWithError(err)
+			return
 		}
-		mpw.Close()
+		w.CloseWithError(mpw.Close())
 	}()
 	var request *net.Request
 	request, err := repo.gateway.NewRequest("PUT", repo.config.APIEndpoint()+apiURL, repo.config.AccessToken(), nil)
@@ -272,10 +266,7 @@ func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string,
 
 	response := &resources.Resource{}
 	_, err = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.APIEndpoint(), request, response, DefaultAppUploadBitsTimeout)
-	if err != nil {
-		panic(err)
-	}
-	return nil
+	return err
 }
 
 func (repo CloudControllerApplicationBitsRepository) predictPart(filesize int64, boundary string) int64 {
@@ -0,0 +1,117 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccerror"
+	"code.cloudfoundry.org/cli/api/uaa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type AuthErrorType string
+
+const (
+	AuthErrorBadCredentials      AuthErrorType = "bad credentials"
+	AuthErrorInvalidRefreshToken AuthErrorType = "invalid or expired token"
+	AuthErrorWrongUaaEndpoint    AuthErrorType = "wrong uaa endpoint"
+	AuthErrorTLS                 AuthErrorType = "tls failure"
+	AuthErrorUnreachable         AuthErrorType = "unreachable endpoint"
+	AuthErrorUnknown             AuthErrorType = "authentication failure"
+)
+
+var authErrorHints = map[AuthErrorType]string{
+	AuthErrorBadCredentials:      "check 'username', 'password' and 'origin' or 'client_id' and 'client_secret' given to the provider",
+	AuthErrorInvalidRefreshToken: "your tokens are expired or revoked, login again with the cf cli or give new 'user_access_token' and 'user_refresh_token'",
+	AuthErrorWrongUaaEndpoint:    "the authorization endpoint doesn't answer as an UAA, check that 'api_endpoint' targets the api of a Cloud Foundry",
+	AuthErrorTLS:                 "the certificate of the endpoint can't be verified, give its ca with 'ca_cert' or set 'skip_ssl_validation' to true if it is self-signed",
+	AuthErrorUnreachable:         "check that 'api_endpoint' is correct and reachable from where terraform runs (dns, proxy or firewall)",
+	AuthErrorUnknown:             "set 'verbose' to true and use TF_LOG=DEBUG to see requests sent to Cloud Foundry",
+}
+
+// AuthError is returned when the provider can't reach or authenticate on Cloud Foundry
+// it gives the endpoint contacted and a hint to fix the problem.
+type AuthError struct {
+	Type     AuthErrorType
+	Endpoint string
+	Hint     string
+	Err      error
+}
+
+func (e AuthError) Error() string {
+	return fmt.Sprintf("%s when contacting '%s': %s (hint: %s)", e.Type, e.Endpoint, e.Err.Error(), e.Hint)
+}
+
+func NewAuthError(err error, endpoint string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(AuthError); ok {
+		return err
+	}
+	errType := authErrorType(err)
+	return AuthError{
+		Type:     errType,
+		Endpoint: endpoint,
+		Hint:     authErrorHints[errType],
+		Err:      err,
+	}
+}
+func authErrorType(err error) AuthErrorType {
+	switch e := err.(type) {
+	case uaa.UnauthorizedError:
+		return AuthErrorBadCredentials
+	case uaa.InvalidAuthTokenError:
+		return AuthErrorInvalidRefreshToken
+	case uaa.UnverifiedServerError, ccerror.UnverifiedServerError, ccerror.SSLValidationHostnameError:
+		return AuthErrorTLS
+	case ccerror.APINotFoundError:
+		return AuthErrorUnreachable
+	case uaa.RequestError:
+		return requestErrorType(e.Err)
+	case ccerror.RequestError:
+		return requestErrorType(e.Err)
+	case uaa.RawHTTPStatusError:
+		return rawStatusErrorType(e.StatusCode, e.RawResponse)
+	case ccerror.RawHTTPStatusError:
+		return rawStatusErrorType(e.StatusCode, e.RawResponse)
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		// the endpoint answered something which is not an uaa response
+		return AuthErrorWrongUaaEndpoint
+	}
+	return AuthErrorUnknown
+}
+func requestErrorType(err error) AuthErrorType {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	switch err.(type) {
+	case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError, tls.RecordHeaderError:
+		return AuthErrorTLS
+	}
+	// certificate errors can be wrapped by the tls package
+	if strings.Contains(err.Error(), "x509:") {
+		return AuthErrorTLS
+	}
+	return AuthErrorUnreachable
+}
+func rawStatusErrorType(statusCode int, rawResponse []byte) AuthErrorType {
+	var uaaErr uaa.UAAErrorResponse
+	err := json.Unmarshal(rawResponse, &uaaErr)
+	if err != nil || statusCode == http.StatusNotFound {
+		return AuthErrorWrongUaaEndpoint
+	}
+	switch uaaErr.Type {
+	case "invalid_token", "invalid_grant":
+		return AuthErrorInvalidRefreshToken
+	case "unauthorized", "invalid_client":
+		return AuthErrorBadCredentials
+	}
+	if statusCode == http.StatusUnauthorized {
+		return AuthErrorBadCredentials
+	}
+	return AuthErrorUnknown
+}
@@ -0,0 +1,93 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"code.cloudfoundry.org/cli/api/uaa"
+	"code.cloudfoundry.org/cli/api/uaa/constant"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("AuthError", func() {
+	var server *httptest.Server
+	var tokenHandler http.HandlerFunc
+	var uaaClient *uaa.Client
+	loginHandler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"links":{"uaa":"http://%s","login":"http://%s"}}`, r.Host, r.Host)
+	}
+	BeforeEach(func() {
+		tokenHandler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/login", loginHandler)
+		mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+			tokenHandler(w, r)
+		})
+		server = httptest.NewServer(mux)
+		uaaClient = uaa.NewClient(NewTerraformRepository("tf-provider", "test", false))
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	authError := func(err error) AuthError {
+		Expect(err).To(HaveOccurred())
+		authErr, ok := NewAuthError(err, server.URL).(AuthError)
+		Expect(ok).To(BeTrue())
+		Expect(authErr.Endpoint).To(Equal(server.URL))
+		Expect(authErr.Hint).ToNot(BeEmpty())
+		Expect(authErr.Error()).To(ContainSubstring(server.URL))
+		return authErr
+	}
+	It("should return nil when there is no error", func() {
+		Expect(NewAuthError(nil, server.URL)).To(BeNil())
+	})
+	It("should classify bad credentials", func() {
+		tokenHandler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"unauthorized","error_description":"Bad credentials"}`)
+		}
+		Expect(uaaClient.SetupResources(server.URL)).To(Succeed())
+		_, _, err := uaaClient.Authenticate("user", "wrong", "", constant.GrantTypePassword)
+		Expect(authError(err).Type).To(Equal(AuthErrorBadCredentials))
+	})
+	It("should classify bad client credentials", func() {
+		tokenHandler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"Bad client credentials"}`)
+		}
+		Expect(uaaClient.SetupResources(server.URL)).To(Succeed())
+		_, _, err := uaaClient.Authenticate("client", "wrong", "", constant.GrantTypeClientCredentials)
+		Expect(authError(err).Type).To(Equal(AuthErrorBadCredentials))
+	})
+	It("should classify expired refresh token", func() {
+		tokenHandler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_token","error_description":"Invalid refresh token (expired)"}`)
+		}
+		Expect(uaaClient.SetupResources(server.URL)).To(Succeed())
+		_, err := uaaClient.RefreshAccessToken("expired-token")
+		Expect(authError(err).Type).To(Equal(AuthErrorInvalidRefreshToken))
+	})
+	It("should classify wrong uaa endpoint", func() {
+		err := uaaClient.SetupResources(server.URL + "/not-an-uaa")
+		Expect(authError(err).Type).To(Equal(AuthErrorWrongUaaEndpoint))
+	})
+	It("should classify tls failures", func() {
+		tlsServer := httptest.NewTLSServer(http.HandlerFunc(loginHandler))
+		defer tlsServer.Close()
+		err := uaaClient.SetupResources(tlsServer.URL)
+		Expect(authError(err).Type).To(Equal(AuthErrorTLS))
+	})
+	It("should classify unreachable endpoint", func() {
+		unreachable := httptest.NewServer(http.HandlerFunc(loginHandler))
+		unreachable.Close()
+		err := uaaClient.SetupResources(unreachable.URL)
+		Expect(authError(err).Type).To(Equal(AuthErrorUnreachable))
+	})
+})
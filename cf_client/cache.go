@@ -0,0 +1,131 @@
+package cf_client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// cachablePaths are lookups done many times during a run: orgs, spaces, domains, stacks and service plans
+var cachablePaths = []*regexp.Regexp{
+	regexp.MustCompile(`^/v[23]/(organizations|spaces|private_domains|shared_domains|domains|stacks|service_plans)(/[^/]+)?$`),
+	regexp.MustCompile(`^/v2/organizations/[^/]+/(spaces|private_domains|domains)$`),
+	regexp.MustCompile(`^/v2/services/[^/]+/service_plans$`),
+}
+
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// LookupCache keeps lookup responses for a provider session,
+// every entries are dropped when a mutating request is made.
+type LookupCache struct {
+	entries    map[string]cachedResponse
+	generation int
+	mutex      *sync.RWMutex
+}
+
+func NewLookupCache() *LookupCache {
+	return &LookupCache{
+		entries: make(map[string]cachedResponse),
+		mutex:   new(sync.RWMutex),
+	}
+}
+func (c *LookupCache) get(key string) (cachedResponse, int, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	entry, ok := c.entries[key]
+	return entry, c.generation, ok
+}
+
+// set stores response only if cache was not invalidated since the request was sent
+func (c *LookupCache) set(key string, generation int, entry cachedResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.entries[key] = entry
+}
+func (c *LookupCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]cachedResponse)
+	c.generation++
+}
+
+// CacheTransport answers lookups from a LookupCache and invalidates it on mutating requests
+type CacheTransport struct {
+	cache     *LookupCache
+	transport http.RoundTripper
+}
+
+func NewCacheTransport(cache *LookupCache, transport http.RoundTripper) *CacheTransport {
+	return &CacheTransport{
+		cache:     cache,
+		transport: transport,
+	}
+}
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead && !strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		t.cache.Invalidate()
+		// invalidate again when done, lookups made during mutation could be outdated
+		defer t.cache.Invalidate()
+		return t.transport.RoundTrip(req)
+	}
+	if req.Method != http.MethodGet || !isCachable(req) {
+		return t.transport.RoundTrip(req)
+	}
+	key := req.URL.String()
+	entry, generation, ok := t.cache.get(key)
+	if ok {
+		return entry.toResponse(req), nil
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	entry = cachedResponse{
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		body:       body,
+	}
+	t.cache.set(key, generation, entry)
+	return entry.toResponse(req), nil
+}
+func isCachable(req *http.Request) bool {
+	for _, path := range cachablePaths {
+		if path.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+func (e cachedResponse) toResponse(req *http.Request) *http.Response {
+	header := make(http.Header)
+	for key, values := range e.header {
+		header[key] = append([]string{}, values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.statusCode, http.StatusText(e.statusCode)),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
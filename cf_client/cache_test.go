@@ -0,0 +1,58 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe("CacheTransport", func() {
+	var server *httptest.Server
+	var calls map[string]int
+	var client *http.Client
+	BeforeEach(func() {
+		calls = make(map[string]int)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls[r.Method+" "+r.URL.Path]++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"resources":[]}`))
+		}))
+		client = &http.Client{Transport: NewCacheTransport(NewLookupCache(), http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	get := func(path string) string {
+		resp, err := client.Get(server.URL + path)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return string(b)
+	}
+	It("should answer lookups from cache", func() {
+		Expect(get("/v2/organizations")).To(Equal(`{"resources":[]}`))
+		Expect(get("/v2/organizations")).To(Equal(`{"resources":[]}`))
+		get("/v2/organizations/guid/spaces")
+		get("/v2/organizations/guid/spaces")
+		Expect(calls["GET /v2/organizations"]).To(Equal(1))
+		Expect(calls["GET /v2/organizations/guid/spaces"]).To(Equal(1))
+	})
+	It("should not cache other requests", func() {
+		get("/v2/apps/guid")
+		get("/v2/apps/guid")
+		Expect(calls["GET /v2/apps/guid"]).To(Equal(2))
+	})
+	It("should invalidate cache on mutating request", func() {
+		get("/v2/spaces")
+		_, err := client.Post(server.URL+"/v2/spaces", "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		get("/v2/spaces")
+		Expect(calls["GET /v2/spaces"]).To(Equal(2))
+	})
+})
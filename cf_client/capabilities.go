@@ -0,0 +1,130 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccversion"
+	"fmt"
+	"github.com/blang/semver"
+)
+
+// Feature describes what a feature needs from Cloud Foundry,
+// an empty min version means that any version is accepted.
+type Feature struct {
+	Name                  string
+	MinV2Version          string
+	MinV3Version          string
+	RequiresV3            bool
+	RequiresRoutingAPI    bool
+	RequiresNetworkPolicy bool
+}
+
+var (
+	FeatureIsolationSegments = Feature{
+		Name:         "isolation segments",
+		RequiresV3:   true,
+		MinV3Version: ccversion.MinVersionIsolationSegmentV3,
+	}
+	FeatureV3Applications = Feature{
+		Name:         "v3 applications",
+		RequiresV3:   true,
+		MinV3Version: ccversion.MinVersionApplicationFlowV3,
+	}
+	FeatureRouterGroups = Feature{
+		Name:               "router groups",
+		RequiresRoutingAPI: true,
+	}
+	FeatureNetworkPolicies = Feature{
+		Name:                  "network policies",
+		RequiresV3:            true,
+		RequiresNetworkPolicy: true,
+		MinV3Version:          ccversion.MinVersionNetworkingV3,
+	}
+	FeatureBuildpackStack = Feature{
+		Name:         "buildpack stack",
+		MinV2Version: ccversion.MinVersionBuildpackStackAssociationV2,
+	}
+	FeatureServiceSharing = Feature{
+		Name:         "service instance sharing",
+		RequiresV3:   true,
+		MinV3Version: ccversion.MinVersionShareServiceV3,
+	}
+	FeatureV3Finder = Feature{
+		Name:         "v3 lookups",
+		RequiresV3:   true,
+		MinV3Version: MinVersionFinderV3,
+	}
+	FeatureDeployments = Feature{
+		Name:         "rolling deployments",
+		RequiresV3:   true,
+		MinV3Version: MinVersionDeploymentsV3,
+	}
+	FeatureProcesses = Feature{
+		Name:         "process types",
+		RequiresV3:   true,
+		MinV3Version: ccversion.MinVersionApplicationFlowV3,
+	}
+	FeatureSidecars = Feature{
+		Name:         "sidecars",
+		RequiresV3:   true,
+		MinV3Version: MinVersionSidecarsV3,
+	}
+)
+
+// MinVersionFinderV3 is the first cloud controller v3 version where every endpoint used by FinderV3
+// is available (routes, domains, security groups, quotas, service instances and credential bindings).
+const MinVersionFinderV3 = "3.99.0"
+
+// MinVersionDeploymentsV3 is the first cloud controller v3 version where deployments can be created and canceled
+const MinVersionDeploymentsV3 = "3.55.0"
+
+// MinVersionSidecarsV3 is the first cloud controller v3 version where sidecars can be managed
+const MinVersionSidecarsV3 = "3.62.0"
+
+// Capabilities is what the targeted Cloud Foundry supports, found in /v2/info and in cloud controller v3 root
+type Capabilities struct {
+	V2Version              string
+	V3Version              string
+	V3Available            bool
+	RoutingAPIAvailable    bool
+	NetworkPolicyAvailable bool
+}
+
+// Require gives an error explaining the first requirement of features not met by Cloud Foundry
+func (c Capabilities) Require(features ...Feature) error {
+	for _, feature := range features {
+		if err := c.require(feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c Capabilities) Supports(features ...Feature) bool {
+	return c.Require(features...) == nil
+}
+func (c Capabilities) require(feature Feature) error {
+	if feature.RequiresV3 && !c.V3Available {
+		return fmt.Errorf("feature '%s' requires CC API v3 which is not available on this Cloud Foundry", feature.Name)
+	}
+	if feature.RequiresRoutingAPI && !c.RoutingAPIAvailable {
+		return fmt.Errorf("feature '%s' requires the routing API which is not available on this Cloud Foundry", feature.Name)
+	}
+	if feature.RequiresNetworkPolicy && !c.NetworkPolicyAvailable {
+		return fmt.Errorf("feature '%s' requires the network policy API which is not available on this Cloud Foundry", feature.Name)
+	}
+	if !isMinVersion(c.V2Version, feature.MinV2Version) {
+		return fmt.Errorf("feature '%s' requires CC API >= %s (found: %s)", feature.Name, feature.MinV2Version, c.V2Version)
+	}
+	if !isMinVersion(c.V3Version, feature.MinV3Version) {
+		return fmt.Errorf("feature '%s' requires CC API v3 >= %s (found: %s)", feature.Name, feature.MinV3Version, c.V3Version)
+	}
+	return nil
+}
+func isMinVersion(current, minimum string) bool {
+	if minimum == "" {
+		return true
+	}
+	currentSemver, err := semver.Make(current)
+	if err != nil {
+		return false
+	}
+	return currentSemver.GTE(semver.MustParse(minimum))
+}
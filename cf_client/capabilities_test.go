@@ -0,0 +1,42 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capabilities", func() {
+	var capabilities Capabilities
+	BeforeEach(func() {
+		capabilities = Capabilities{
+			V2Version:   "2.100.0",
+			V3Version:   "3.10.0",
+			V3Available: true,
+		}
+	})
+	It("should accept features supported", func() {
+		Expect(capabilities.Require(Feature{Name: "my feature", MinV2Version: "2.99.0"})).To(Succeed())
+		Expect(capabilities.Supports(Feature{Name: "my feature", RequiresV3: true})).To(BeTrue())
+	})
+	It("should give minimum version required when cloud controller is too old", func() {
+		err := capabilities.Require(FeatureIsolationSegments)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("feature 'isolation segments' requires CC API v3 >= 3.11.0 (found: 3.10.0)"))
+
+		err = capabilities.Require(FeatureBuildpackStack)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("requires CC API >= 2.114.0"))
+	})
+	It("should use v3 lookups only when every v3 endpoint needed is available", func() {
+		Expect(capabilities.Supports(FeatureV3Finder)).To(BeFalse())
+		capabilities.V3Version = MinVersionFinderV3
+		Expect(capabilities.Supports(FeatureV3Finder)).To(BeTrue())
+	})
+	It("should explain which api is missing", func() {
+		capabilities.V3Available = false
+		Expect(capabilities.Require(FeatureIsolationSegments)).To(MatchError(ContainSubstring("requires CC API v3")))
+		Expect(capabilities.Require(FeatureRouterGroups)).To(MatchError(ContainSubstring("requires the routing API")))
+	})
+})
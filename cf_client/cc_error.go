@@ -0,0 +1,254 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccerror"
+	"code.cloudfoundry.org/cli/cf/errors"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxRecentCCErrors is the number of error responses kept to enrich errors from cf/net gateways
+const maxRecentCCErrors = 100
+
+// CCError is an error given by Cloud Controller with what is needed to correlate it with Cloud Controller logs,
+// only Description is always set.
+type CCError struct {
+	// Code is the numeric Cloud Controller code (e.g.: 100005)
+	Code int
+	// ErrorCode is the Cloud Controller error name (e.g.: CF-AppMemoryQuotaExceeded)
+	ErrorCode   string
+	Description string
+	StatusCode  int
+	RequestID   string
+	// Resource is the address of the resource which got the error (e.g.: cloudfoundry_app.<guid>)
+	Resource string
+	Err      error
+}
+
+func (e *CCError) Error() string {
+	details := make([]string, 0)
+	if e.ErrorCode != "" {
+		details = append(details, "error_code: "+e.ErrorCode)
+	}
+	if e.Code != 0 {
+		details = append(details, fmt.Sprintf("code: %d", e.Code))
+	}
+	if e.StatusCode != 0 {
+		details = append(details, fmt.Sprintf("status: %d", e.StatusCode))
+	}
+	if e.RequestID != "" {
+		details = append(details, "X-Vcap-Request-Id: "+e.RequestID)
+	}
+	message := e.Description
+	if len(details) > 0 {
+		message = fmt.Sprintf("%s (%s)", message, strings.Join(details, ", "))
+	}
+	if e.Resource != "" {
+		message = e.Resource + ": " + message
+	}
+	return message
+}
+
+// NewCCError converts an error from cf/net gateways, ccv2 or ccv3 clients to a CCError,
+// resource is the address of resource which got the error and can be empty.
+func NewCCError(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+	if ccErr, ok := err.(*CCError); ok {
+		if ccErr.Resource == "" {
+			ccErr.Resource = resource
+		}
+		return ccErr
+	}
+	ccErr := &CCError{
+		Description: err.Error(),
+		Resource:    resource,
+		Err:         err,
+	}
+	switch e := err.(type) {
+	case ccerror.V2UnexpectedResponseError:
+		ccErr.Code = e.Code
+		ccErr.ErrorCode = e.ErrorCode
+		ccErr.Description = e.Description
+		ccErr.StatusCode = e.ResponseCode
+		ccErr.RequestID = firstRequestID(e.RequestIDs)
+		return ccErr
+	case ccerror.V3UnexpectedResponseError:
+		ccErr.StatusCode = e.ResponseCode
+		ccErr.RequestID = firstRequestID(e.RequestIDs)
+		if len(e.Errors) > 0 {
+			ccErr.Code = e.Errors[0].Code
+			ccErr.ErrorCode = e.Errors[0].Title
+			ccErr.Description = e.Errors[0].Detail
+		}
+		return ccErr
+	case ccerror.JobFailedError:
+		ccErr.Description = e.Message
+	case errors.HTTPError:
+		ccErr.StatusCode = e.StatusCode()
+		ccErr.Code, _ = strconv.Atoi(e.ErrorCode())
+	}
+	if details, ok := recentCCErrors.find(err, ccErr.Description); ok {
+		ccErr.Code = details.Code
+		ccErr.ErrorCode = details.ErrorCode
+		ccErr.Description = details.Description
+		ccErr.StatusCode = details.StatusCode
+		ccErr.RequestID = details.RequestID
+	}
+	return ccErr
+}
+
+// IsCCError is true when err was given by Cloud Controller, other errors (e.g.: PermissionError, AuthError)
+// must keep their type and not be presented as Cloud Controller errors.
+func IsCCError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch err.(type) {
+	case *CCError, errors.HTTPError:
+		return true
+	}
+	errType := reflect.TypeOf(err)
+	if errType.Kind() == reflect.Ptr {
+		errType = errType.Elem()
+	}
+	if errType.PkgPath() == reflect.TypeOf(ccerror.V2UnexpectedResponseError{}).PkgPath() {
+		return true
+	}
+	_, found := recentCCErrors.find(err, err.Error())
+	return found
+}
+
+// ErrorCode gives the Cloud Controller error name of an error (e.g.: CF-AppMemoryQuotaExceeded), empty if unknown
+func ErrorCode(err error) string {
+	ccErr, ok := NewCCError(err, "").(*CCError)
+	if !ok {
+		return ""
+	}
+	return ccErr.ErrorCode
+}
+func firstRequestID(requestIDs []string) string {
+	if len(requestIDs) == 0 {
+		return ""
+	}
+	return requestIDs[0]
+}
+
+// ccErrorDetails are details of an error response from Cloud Controller v2 or v3
+type ccErrorDetails struct {
+	Code        int
+	ErrorCode   string
+	Description string
+	StatusCode  int
+	RequestID   string
+}
+
+// ccErrorRegistry keeps the last error responses from Cloud Controller,
+// cf/net gateways only keep numeric code and description of errors.
+type ccErrorRegistry struct {
+	sync.Mutex
+	entries []ccErrorDetails
+}
+
+var recentCCErrors = &ccErrorRegistry{}
+
+func (r *ccErrorRegistry) add(details ccErrorDetails) {
+	r.Lock()
+	defer r.Unlock()
+	r.entries = append(r.entries, details)
+	if len(r.entries) > maxRecentCCErrors {
+		r.entries = r.entries[len(r.entries)-maxRecentCCErrors:]
+	}
+}
+
+// find gives the error response matching err. When responses of several requests match (e.g.: same quota error
+// on two apps applied in parallel), their common details are given without request id which can't be known.
+func (r *ccErrorRegistry) find(err error, description string) (ccErrorDetails, bool) {
+	r.Lock()
+	defer r.Unlock()
+	found := false
+	var match ccErrorDetails
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		details := r.entries[i]
+		if !details.matches(err, description) {
+			continue
+		}
+		if !found {
+			match = details
+			found = true
+			continue
+		}
+		if details.RequestID != match.RequestID {
+			match.RequestID = ""
+		}
+	}
+	return match, found
+}
+func (d ccErrorDetails) matches(err error, description string) bool {
+	if httpErr, ok := err.(errors.HTTPError); ok {
+		if d.StatusCode != httpErr.StatusCode() || strconv.Itoa(d.Code) != httpErr.ErrorCode() {
+			return false
+		}
+		return errors.NewHTTPError(d.StatusCode, httpErr.ErrorCode(), d.Description).Error() == err.Error()
+	}
+	return d.Description != "" && d.Description == description
+}
+
+// CCErrorTransport keeps error responses from Cloud Controller to enrich errors with NewCCError
+type CCErrorTransport struct {
+	transport http.RoundTripper
+}
+
+func NewCCErrorTransport(transport http.RoundTripper) *CCErrorTransport {
+	return &CCErrorTransport{
+		transport: transport,
+	}
+}
+func (t *CCErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, err
+	}
+	if resp.Body == nil || resp.ContentLength > maxTracedBodyLen {
+		return resp, err
+	}
+	b, complete, err := peekResponseBody(resp)
+	if err != nil || !complete {
+		return resp, nil
+	}
+	details, ok := parseCCError(b)
+	if !ok {
+		return resp, nil
+	}
+	details.StatusCode = resp.StatusCode
+	details.RequestID = resp.Header.Get("X-Vcap-Request-Id")
+	recentCCErrors.add(details)
+	return resp, nil
+}
+
+// parseCCError reads an error response from Cloud Controller v2 or v3
+func parseCCError(b []byte) (ccErrorDetails, bool) {
+	var v2Err ccerror.V2ErrorResponse
+	if json.Unmarshal(b, &v2Err) == nil && v2Err.ErrorCode != "" {
+		return ccErrorDetails{
+			Code:        v2Err.Code,
+			ErrorCode:   v2Err.ErrorCode,
+			Description: v2Err.Description,
+		}, true
+	}
+	var v3Err ccerror.V3ErrorResponse
+	if json.Unmarshal(b, &v3Err) == nil && len(v3Err.Errors) > 0 {
+		return ccErrorDetails{
+			Code:        v3Err.Errors[0].Code,
+			ErrorCode:   v3Err.Errors[0].Title,
+			Description: v3Err.Errors[0].Detail,
+		}, true
+	}
+	return ccErrorDetails{}, false
+}
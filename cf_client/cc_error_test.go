@@ -0,0 +1,90 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccerror"
+	"code.cloudfoundry.org/cli/cf/errors"
+	"code.cloudfoundry.org/cli/cf/i18n"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("CCError", func() {
+	It("should keep details of a cloud controller v3 error", func() {
+		err := NewCCError(ccerror.V3UnexpectedResponseError{
+			ResponseCode: http.StatusUnprocessableEntity,
+			RequestIDs:   []string{"request-id"},
+			V3ErrorResponse: ccerror.V3ErrorResponse{
+				Errors: []ccerror.V3Error{{Code: 100005, Title: "CF-AppMemoryQuotaExceeded", Detail: "memory quota exceeded"}},
+			},
+		}, "cloudfoundry_app.guid")
+		Expect(err).To(BeAssignableToTypeOf(&CCError{}))
+		ccErr := err.(*CCError)
+		Expect(ccErr.ErrorCode).To(Equal("CF-AppMemoryQuotaExceeded"))
+		Expect(ccErr.Code).To(Equal(100005))
+		Expect(ccErr.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		Expect(ccErr.RequestID).To(Equal("request-id"))
+		Expect(err.Error()).To(Equal("cloudfoundry_app.guid: memory quota exceeded (error_code: CF-AppMemoryQuotaExceeded, code: 100005, status: 422, X-Vcap-Request-Id: request-id)"))
+	})
+	It("should find error name and request id of a cf/net gateway error from its response", func() {
+		i18n.T = func(translationID string, args ...interface{}) string {
+			return translationID
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Vcap-Request-Id", "gateway-request-id")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":100007,"description":"quota exceeded for instances","error_code":"CF-AppInstanceQuotaExceeded"}`))
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewCCErrorTransport(http.DefaultTransport)}
+		resp, err := client.Get(server.URL + "/v2/apps")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		ccErr := NewCCError(errors.NewHTTPError(http.StatusBadRequest, "100007", "quota exceeded for instances"), "").(*CCError)
+		Expect(ccErr.ErrorCode).To(Equal("CF-AppInstanceQuotaExceeded"))
+		Expect(ccErr.RequestID).To(Equal("gateway-request-id"))
+		Expect(ccErr.Description).To(Equal("quota exceeded for instances"))
+		Expect(ErrorCode(ccErr)).To(Equal("CF-AppInstanceQuotaExceeded"))
+	})
+	It("should not give a request id when responses of several requests match the error", func() {
+		i18n.T = func(translationID string, args ...interface{}) string {
+			return translationID
+		}
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Vcap-Request-Id", fmt.Sprintf("request-id-%d", requests))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":100005,"description":"memory quota exceeded for parallel apps","error_code":"CF-AppMemoryQuotaExceeded"}`))
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewCCErrorTransport(http.DefaultTransport)}
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(server.URL + "/v2/apps")
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+		}
+
+		ccErr := NewCCError(errors.NewHTTPError(http.StatusBadRequest, "100005", "memory quota exceeded for parallel apps"), "").(*CCError)
+		Expect(ccErr.ErrorCode).To(Equal("CF-AppMemoryQuotaExceeded"))
+		Expect(ccErr.RequestID).To(BeEmpty())
+	})
+	It("should give nil on nil error", func() {
+		Expect(NewCCError(nil, "cloudfoundry_app.guid")).To(BeNil())
+	})
+	It("should only recognize errors given by cloud controller", func() {
+		Expect(IsCCError(errors.NewHTTPError(http.StatusNotFound, "10000", "Unknown request"))).To(BeTrue())
+		Expect(IsCCError(ccerror.ResourceNotFoundError{Message: "App not found"})).To(BeTrue())
+		Expect(IsCCError(&CCError{Description: "Staging failed"})).To(BeTrue())
+		Expect(IsCCError(PermissionError{Operation: "Creating a shared domain"})).To(BeFalse())
+		Expect(IsCCError(fmt.Errorf("app my-app is protected"))).To(BeFalse())
+		Expect(IsCCError(nil)).To(BeFalse())
+	})
+})
@@ -3,11 +3,31 @@ package cf_client_test
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
 
+	"code.cloudfoundry.org/cli/cf/i18n"
+	"code.cloudfoundry.org/cli/cf/net"
+	"code.cloudfoundry.org/cli/cf/terminal"
+	"code.cloudfoundry.org/cli/cf/trace"
+	"io/ioutil"
 	"testing"
+	"time"
 )
 
 func TestCfClient(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "CfClient Suite")
 }
+
+// newTestGateway gives a cloud controller gateway and its configuration targeting a test server
+func newTestGateway(apiEndpoint string) (*TerraformRepository, net.Gateway) {
+	repository := NewTerraformRepository("terraform-provider-cloudfoundry", "test", false)
+	repository.SetAPIEndpoint(apiEndpoint)
+	repository.SetAccessToken("bearer token")
+	// gateway translates and colors what it logs
+	i18n.T = i18n.Init(repository)
+	terminal.InitColorSupport()
+	logger := trace.NewLogger(ioutil.Discard, false, "", "")
+	ui := terminal.NewUI(ioutil.NopCloser(nil), ioutil.Discard, terminal.NewTeePrinter(ioutil.Discard), logger)
+	return repository, net.NewCloudControllerGateway(repository, time.Now, ui, logger, "5")
+}
@@ -0,0 +1,112 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/util/configv3"
+	"encoding/json"
+	"fmt"
+	"github.com/mitchellh/go-homedir"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CliConfig wraps a config.json file written by the cf CLI (e.g. after a `cf login`).
+// Tokens refreshed by the provider are written back to keep the cli session valid.
+type CliConfig struct {
+	path       string
+	jsonConfig configv3.JSONConfig
+	mutex      *sync.Mutex
+}
+
+func DefaultCliConfigPath() string {
+	cfHome := os.Getenv("CF_HOME")
+	if cfHome == "" {
+		cfHome, _ = homedir.Dir()
+	}
+	return filepath.Join(cfHome, ".cf", "config.json")
+}
+
+func LoadCliConfig(path string) (*CliConfig, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var jsonConfig configv3.JSONConfig
+	err = json.Unmarshal(b, &jsonConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Error when reading cf cli config file '%s': %s", path, err.Error())
+	}
+	return &CliConfig{
+		path:       path,
+		jsonConfig: jsonConfig,
+		mutex:      new(sync.Mutex),
+	}, nil
+}
+func (c *CliConfig) Path() string {
+	return c.path
+}
+func (c *CliConfig) JSONConfig() configv3.JSONConfig {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.jsonConfig
+}
+
+// SaveTokens writes back tokens in cli config file read again, other fields are kept as they are now in file
+// (e.g.: a target changed with cf cli during a run).
+func (c *CliConfig) SaveTokens(accessToken, refreshToken string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.jsonConfig.AccessToken == accessToken && c.jsonConfig.RefreshToken == refreshToken {
+		return nil
+	}
+	b, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return fmt.Errorf("Error when reading cf cli config file '%s': %s", c.path, err.Error())
+	}
+	fields["AccessToken"], _ = json.Marshal(accessToken)
+	fields["RefreshToken"], _ = json.Marshal(refreshToken)
+	b, err = json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomically(c.path, b)
+	if err != nil {
+		return err
+	}
+	c.jsonConfig.AccessToken = accessToken
+	c.jsonConfig.RefreshToken = refreshToken
+	return nil
+}
+
+// writeFileAtomically writes content in a temporary file renamed to path,
+// cf cli never reads a partially written file.
+func writeFileAtomically(path string, content []byte) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	err = os.Rename(tmpFile.Name(), path)
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3"
 	ccWrapper "code.cloudfoundry.org/cli/api/cloudcontroller/wrapper"
 	"code.cloudfoundry.org/cli/api/uaa"
+	"code.cloudfoundry.org/cli/api/uaa/constant"
 	uaaWrapper "code.cloudfoundry.org/cli/api/uaa/wrapper"
 	"code.cloudfoundry.org/cli/cf/api"
 	"code.cloudfoundry.org/cli/cf/api/appinstances"
@@ -24,15 +25,33 @@ import (
 	"code.cloudfoundry.org/cli/cf/api/stacks"
 	"code.cloudfoundry.org/cli/cf/appfiles"
 	"code.cloudfoundry.org/cli/cf/i18n"
+	"code.cloudfoundry.org/cli/cf/models"
 	"code.cloudfoundry.org/cli/cf/net"
 	"code.cloudfoundry.org/cli/cf/trace"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/encryption"
 	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
 	"time"
 )
 
 type Client interface {
+	Connect() error
+	// Context is canceled when terraform is interrupted, it must be given to long running operations
+	Context() context.Context
+	// Compensate runs f with a copy of client whose requests and long running operations are not aborted
+	// when Context is canceled, requests made by others with this client are still aborted
+	Compensate(f func(client Client) error) error
+	// ForResource runs f with a copy of client whose changes are recorded in journal with address of resource,
+	// f is run with client itself when there is no journal
+	ForResource(address func() string, f func(client Client) error) error
 	Gateways() CloudFoundryGateways
 	Finder() FinderRepository
 	Organizations() organizations.OrganizationRepository
@@ -64,7 +83,19 @@ type Client interface {
 	AppInstances() appinstances.Repository
 	ApplicationBits() bitsmanager.ApplicationBitsRepository
 	Logs() logs.Repository
+	Deployments() DeploymentRepository
+	Processes() ProcessRepository
+	Sidecars() SidecarRepository
 	CCv3Client() *ccv3.Client
+	HTTPClient() *http.Client
+	TLSConfig() *tls.Config
+	Proxy() ProxyFunc
+	Capabilities() Capabilities
+	DefaultOrg() models.OrganizationFields
+	DefaultSpace() models.SpaceFields
+	Journal() *MutationJournal
+	// Close closes files opened by client (e.g.: 'trace_file' and 'journal_file')
+	Close() error
 }
 type CfClient struct {
 	config                      Config
@@ -98,39 +129,116 @@ type CfClient struct {
 	appInstances                appinstances.Repository
 	applicationBits             bitsmanager.ApplicationBitsRepository
 	logs                        logs.Repository
+	deployments                 DeploymentRepository
+	processes                   ProcessRepository
+	sidecars                    SidecarRepository
 	ccv3Client                  *ccv3.Client
+	detachedCCv3                *detachedCCv3Client
 	uaaRepo                     authentication.UAARepository
 	uaaClient                   *uaa.Client
+	httpClient                  *http.Client
+	tlsConfig                   *tls.Config
+	proxy                       ProxyFunc
+	capabilities                Capabilities
+	defaultOrg                  models.OrganizationFields
+	defaultSpace                models.SpaceFields
+	journal                     *MutationJournal
+	tracer                      *RequestTracer
+	connectOnce                 *sync.Once
+	connectErr                  error
+	providerCtx                 ProviderContext
+	logger                      trace.Printer
 }
 
-func NewCfClient(config Config) (Client, error) {
-	cfClient := &CfClient{config: config}
-	err := cfClient.Init()
+// NewCfClient creates a client which connects to Cloud Foundry on first call to Connect,
+// only configuration errors are given.
+// Requests and long running operations are aborted when ctx is canceled.
+func NewCfClient(ctx context.Context, config Config) (Client, error) {
+	if config.Retry.MaxAttempts <= 0 {
+		config.Retry = common.DefaultRetryPolicy()
+	}
+	cfClient := &CfClient{
+		config:      config,
+		connectOnce: new(sync.Once),
+		providerCtx: NewProviderContext(ctx),
+	}
+	err := cfClient.LoadHTTPClient()
 	if err != nil {
 		return nil, err
 	}
-	return cfClient, err
+	return cfClient, nil
 }
-func (client *CfClient) Init() error {
 
+// Connect targets Cloud Foundry, authenticates and loads repositories on first call,
+// next calls give the same result.
+func (client *CfClient) Connect() error {
+	client.connectOnce.Do(func() {
+		client.connectErr = client.Init()
+	})
+	return client.connectErr
+}
+func (client *CfClient) LoadHTTPClient() error {
+	if client.providerCtx.ctx == nil {
+		client.providerCtx = NewProviderContext(context.Background())
+	}
+	tlsConfig, err := NewTLSConfig(client.config)
+	if err != nil {
+		return err
+	}
+	client.tlsConfig = tlsConfig
+	client.proxy, err = NewProxyFunc(client.config)
+	if err != nil {
+		return err
+	}
+	// files of a previous load would never be closed
+	client.Close()
+	if client.config.TraceFile != "" {
+		client.tracer, err = NewRequestTracer(client.config.TraceFile)
+		if err != nil {
+			return fmt.Errorf("Error when opening 'trace_file': %s", err.Error())
+		}
+	}
+	if client.config.JournalFile != "" {
+		client.journal, err = NewMutationJournal(client.config.JournalFile)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("Error when opening 'journal_file': %s", err.Error())
+		}
+	}
+	client.httpClient = NewHTTPClient(client.providerCtx, client.config, tlsConfig, client.proxy, client.tracer, client.journal)
+	return nil
+}
+func (client *CfClient) Init() error {
+	retryPolicy := client.config.Retry
+	if client.config.ApiEndpoint == "" {
+		return errors.New("You must provide an 'api_endpoint' or log in with the cf cli first")
+	}
+	if client.httpClient == nil {
+		err := client.LoadHTTPClient()
+		if err != nil {
+			return err
+		}
+	}
 	ccClient := ccv2.NewClient(ccv2.Config{
 		AppName:            client.config.AppName,
 		AppVersion:         client.config.AppVersion,
 		JobPollingInterval: time.Duration(2) * time.Second,
-		JobPollingTimeout:  time.Duration(10) * time.Second,
+		JobPollingTimeout:  retryPolicy.JobTimeout,
+		Wrappers:           []ccv2.ConnectionWrapper{NewCCHTTPClientWrapper(client.httpClient)},
 	})
 	_, err := ccClient.TargetCF(ccv2.TargetSettings{
-		DialTimeout:       time.Duration(1) * time.Second,
+		DialTimeout:       retryPolicy.DialTimeout,
 		URL:               client.config.Target(),
 		SkipSSLValidation: client.config.SkipSSLValidation(),
 	})
 	if err != nil {
-		return err
+		return NewAuthError(err, client.config.Target())
 	}
 	repository := NewTerraformRepository(client.config.AppName, client.config.AppVersion, client.config.SkipInsecureSSL)
 	repository.SetAPIEndpoint(client.config.ApiEndpoint)
 	repository.SetAPIVersion(ccClient.APIVersion())
-	repository.SetAsyncTimeout(uint(30))
+	repository.SetAsyncTimeout(retryPolicy.JobTimeoutMinutes())
+	repository.SetDialTimeout(retryPolicy.DialTimeout)
 	repository.SetAuthenticationEndpoint(ccClient.AuthorizationEndpoint())
 	repository.SetDopplerEndpoint(ccClient.DopplerEndpoint())
 	repository.SetRoutingAPIEndpoint(ccClient.RoutingEndpoint())
@@ -138,22 +246,40 @@ func (client *CfClient) Init() error {
 	repository.SetSSHOAuthClient(client.config.ClientID())
 	repository.SetAccessToken(client.config.AccessToken())
 	repository.SetRefreshToken(client.config.RefreshToken())
+	err = repository.SetTokenFiles(client.config.AccessTokenFile, client.config.RefreshTokenFile)
+	if err != nil {
+		return fmt.Errorf("Error when reading token files: %s", err.Error())
+	}
 	repository.SetUaaEndpoint(ccClient.TokenEndpoint())
-	repository.SetUAAOAuthClient("cf")
-	repository.SetUAAOAuthClientSecret("")
+	if client.config.IsClientCredentials() {
+		repository.SetUAAOAuthClient(client.config.UaaClientID)
+		repository.SetUAAOAuthClientSecret(client.config.UaaClientSecret)
+		repository.SetUAAGrantType(string(constant.GrantTypeClientCredentials))
+	} else {
+		repository.SetUAAOAuthClient("cf")
+		repository.SetUAAOAuthClientSecret("")
+	}
 	repository.SetLocale(client.config.Locale)
+	if client.config.CliConfig != nil {
+		client.loadCliConfig(repository)
+	}
 	i18n.T = i18n.Init(repository)
-	//Retry Wrapper
 	logger := NewCfLogger(client.config.Verbose)
+	client.logger = logger
 	client.uaaClient = uaa.NewClient(repository)
+	client.uaaClient.WrapConnection(NewUAAHTTPClientWrapper(client.httpClient))
 	err = client.uaaClient.SetupResources(ccClient.AuthorizationEndpoint())
 	if err != nil {
-		return err
+		return NewAuthError(err, ccClient.AuthorizationEndpoint())
 	}
 
 	client.uaaClient.WrapConnection(uaaWrapper.NewUAAAuthentication(client.uaaClient, repository))
-	client.uaaClient.WrapConnection(uaaWrapper.NewRetryRequest(2))
-
+	RegisterGatewayHTTPClient(client.httpClient,
+		repository.APIEndpoint(),
+		repository.AuthenticationEndpoint(),
+		repository.UaaEndpoint(),
+		repository.RoutingAPIEndpoint(),
+	)
 	gateways := NewCloudFoundryGateways(
 		repository,
 		logger,
@@ -170,16 +296,70 @@ func (client *CfClient) Init() error {
 		return err
 	}
 	client.LoadRepositories()
+	err = client.LoadDefaultTarget()
+	if err != nil {
+		return err
+	}
 	client.LoadDecrypter()
-	client.LoadCCv3()
+	errV3 := client.LoadCCv3()
+	if errV3 != nil {
+		log.Printf("[WARN] cloud controller v3 api can't be used: %s", errV3.Error())
+	}
+	client.LoadCapabilities(errV3 == nil)
+	client.LoadFinder()
 	return nil
 }
+func (client *CfClient) LoadCapabilities(v3Available bool) {
+	repository := client.gateways.Config
+	client.capabilities = Capabilities{
+		V2Version:           repository.APIVersion(),
+		V3Available:         v3Available,
+		RoutingAPIAvailable: repository.RoutingAPIEndpoint() != "",
+	}
+	if !v3Available {
+		return
+	}
+	client.capabilities.V3Version = client.ccv3Client.Info.CloudControllerAPIVersion()
+	client.capabilities.NetworkPolicyAvailable = client.ccv3Client.Info.NetworkPolicyV1() != ""
+}
+
+// LoadFinder uses cloud controller v3 for lookups when it supports every endpoint needed, v2 otherwise
+func (client *CfClient) LoadFinder() {
+	if !client.capabilities.Supports(FeatureV3Finder) {
+		client.finder = NewFinderRepository(client.config, client.gateways.CloudControllerGateway)
+		return
+	}
+	log.Printf("[INFO] using cloud controller v3 api (version %s) for lookups", client.capabilities.V3Version)
+	client.finder = NewFinderRepositoryV3(client.config, client.gateways.CloudControllerGateway)
+}
+func (client *CfClient) loadCliConfig(repository *TerraformRepository) {
+	jsonConfig := client.config.CliConfig.JSONConfig()
+	if repository.AuthenticationEndpoint() == "" {
+		repository.SetAuthenticationEndpoint(jsonConfig.AuthorizationEndpoint)
+	}
+	if repository.UaaEndpoint() == "" {
+		repository.SetUaaEndpoint(jsonConfig.UAAEndpoint)
+	}
+	if jsonConfig.UAAOAuthClient != "" {
+		repository.SetUAAOAuthClient(jsonConfig.UAAOAuthClient)
+		repository.SetUAAOAuthClientSecret(jsonConfig.UAAOAuthClientSecret)
+		repository.SetUAAGrantType(jsonConfig.UAAGrantType)
+	}
+	repository.SetCliConfig(client.config.CliConfig)
+}
 func (client *CfClient) LoadCCv3() error {
+	ccClient, err := client.newCCv3Client()
+	if err != nil {
+		return err
+	}
+	client.ccv3Client = ccClient
+	return nil
+}
+func (client CfClient) newCCv3Client() (*ccv3.Client, error) {
 	config := client.gateways.Config
-	ccWrappers := []ccv3.ConnectionWrapper{}
+	ccWrappers := []ccv3.ConnectionWrapper{NewCCHTTPClientWrapper(client.httpClient)}
 	authWrapper := ccWrapper.NewUAAAuthentication(nil, config)
 	ccWrappers = append(ccWrappers, authWrapper)
-	ccWrappers = append(ccWrappers, ccWrapper.NewRetryRequest(2))
 
 	ccClient := ccv3.NewClient(ccv3.Config{
 		AppName:    client.config.AppName,
@@ -187,27 +367,58 @@ func (client *CfClient) LoadCCv3() error {
 		Wrappers:   ccWrappers,
 	})
 	_, err := ccClient.TargetCF(ccv3.TargetSettings{
-		DialTimeout:       time.Duration(1) * time.Second,
+		DialTimeout:       client.config.Retry.DialTimeout,
 		URL:               client.config.Target(),
 		SkipSSLValidation: client.config.SkipSSLValidation(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	authWrapper.SetClient(client.uaaClient)
-	client.ccv3Client = ccClient
-	return nil
+	authWrapper.SetClient(NewTokenFileUAAClient(client.uaaClient, config))
+	return ccClient, nil
 }
 func (client *CfClient) Authenticate() error {
 	if client.config.AccessToken() != "" {
 		return nil
 	}
-	err := client.uaaRepo.Authenticate(map[string]string{"username": client.config.Username, "password": client.config.Password})
+	id := client.config.Username
+	secret := client.config.Password
+	grantType := constant.GrantTypePassword
+	if client.config.IsClientCredentials() {
+		id = client.config.UaaClientID
+		secret = client.config.UaaClientSecret
+		grantType = constant.GrantTypeClientCredentials
+	}
+	accessToken, refreshToken, err := client.uaaClient.Authenticate(id, secret, client.config.Origin, grantType)
 	if err != nil {
-		panic(err)
-		return err
+		return NewAuthError(err, client.gateways.Config.UaaEndpoint())
+	}
+	client.gateways.Config.SetAccessToken(fmt.Sprintf("bearer %s", accessToken))
+	client.gateways.Config.SetRefreshToken(refreshToken)
+	return nil
+}
+
+// LoadDefaultTarget resolves provider default org and space names to use them when resources don't give ids
+func (client *CfClient) LoadDefaultTarget() error {
+	if client.config.DefaultOrg == "" {
+		return nil
+	}
+	org, err := client.organizations.FindByName(client.config.DefaultOrg)
+	if err != nil {
+		return fmt.Errorf("Error when resolving 'default_org' %s: %s", client.config.DefaultOrg, err.Error())
 	}
+	client.defaultOrg = org.OrganizationFields
+	client.gateways.Config.SetOrganizationFields(org.OrganizationFields)
+	if client.config.DefaultSpace == "" {
+		return nil
+	}
+	space, err := client.spaces.FindByNameInOrg(client.config.DefaultSpace, org.GUID)
+	if err != nil {
+		return fmt.Errorf("Error when resolving 'default_space' %s in org %s: %s", client.config.DefaultSpace, org.Name, err.Error())
+	}
+	client.defaultSpace = space.SpaceFields
+	client.gateways.Config.SetSpaceFields(space.SpaceFields)
 	return nil
 }
 func (client *CfClient) LoadDecrypter() {
@@ -216,7 +427,6 @@ func (client *CfClient) LoadDecrypter() {
 func (client *CfClient) LoadRepositories() {
 	gateways := client.gateways
 	repository := gateways.Config
-	client.finder = NewFinderRepository(client.config, gateways.CloudControllerGateway)
 	client.organizations = organizations.NewCloudControllerOrganizationRepository(repository, gateways.CloudControllerGateway)
 	client.spaces = spaces.NewCloudControllerSpaceRepository(repository, gateways.CloudControllerGateway)
 	client.securityGroups = securitygroups.NewSecurityGroupRepo(repository, gateways.CloudControllerGateway)
@@ -242,8 +452,14 @@ func (client *CfClient) LoadRepositories() {
 	client.envVarGroup = environmentvariablegroups.NewCloudControllerRepository(repository, gateways.CloudControllerGateway)
 	client.applications = applications.NewCloudControllerRepository(repository, gateways.CloudControllerGateway)
 	client.appInstances = appinstances.NewCloudControllerAppInstancesRepository(repository, gateways.CloudControllerGateway)
-	client.applicationBits = bitsmanager.NewCloudControllerApplicationBitsRepository(repository, gateways.CloudControllerGateway)
-	client.logs = logs.NewNoaaLogsRepository(repository, NewNOAAClient(repository, client.uaaClient), client.uaaRepo, 30*time.Second)
+	client.applicationBits = bitsmanager.NewCloudControllerApplicationBitsRepository(repository, gateways.CloudControllerGateway, client.httpClient)
+	if client.config.ReadOnly {
+		client.applicationBits = NewReadOnlyApplicationBitsRepository(client.applicationBits)
+	}
+	client.deployments = NewCloudControllerDeploymentRepository(repository, gateways.CloudControllerGateway)
+	client.processes = NewCloudControllerProcessRepository(repository, gateways.CloudControllerGateway)
+	client.sidecars = NewCloudControllerSidecarRepository(repository, gateways.CloudControllerGateway)
+	client.logs = logs.NewNoaaLogsRepository(repository, NewNOAAClient(repository, client.uaaClient, client.tlsConfig, client.proxy), client.uaaRepo, 30*time.Second)
 }
 func (client CfClient) Gateways() CloudFoundryGateways {
 	return client.gateways
@@ -339,8 +555,109 @@ func (client CfClient) ApplicationBits() bitsmanager.ApplicationBitsRepository {
 	return client.applicationBits
 }
 func (client CfClient) CCv3Client() *ccv3.Client {
-	return client.ccv3Client
+	if client.detachedCCv3 == nil {
+		return client.ccv3Client
+	}
+	client.detachedCCv3.once.Do(func() {
+		ccClient, err := client.newCCv3Client()
+		if err != nil {
+			log.Printf("[WARN] cloud controller v3 client can't be detached, requests use provider context: %s", err.Error())
+			ccClient = client.ccv3Client
+		}
+		client.detachedCCv3.ccv3Client = ccClient
+	})
+	return client.detachedCCv3.ccv3Client
+}
+func (client CfClient) Deployments() DeploymentRepository {
+	return client.deployments
+}
+func (client CfClient) Processes() ProcessRepository {
+	return client.processes
+}
+func (client CfClient) Sidecars() SidecarRepository {
+	return client.sidecars
 }
 func (client CfClient) Logs() logs.Repository {
 	return client.logs
 }
+func (client CfClient) HTTPClient() *http.Client {
+	return client.httpClient
+}
+func (client CfClient) TLSConfig() *tls.Config {
+	return client.tlsConfig
+}
+func (client CfClient) Proxy() ProxyFunc {
+	return client.proxy
+}
+func (client CfClient) Capabilities() Capabilities {
+	return client.capabilities
+}
+func (client CfClient) DefaultOrg() models.OrganizationFields {
+	return client.defaultOrg
+}
+func (client CfClient) DefaultSpace() models.SpaceFields {
+	return client.defaultSpace
+}
+func (client CfClient) Journal() *MutationJournal {
+	return client.journal
+}
+func (client *CfClient) Close() error {
+	var err error
+	if client.tracer != nil {
+		err = client.tracer.Close()
+		client.tracer = nil
+	}
+	if client.journal != nil {
+		if journalErr := client.journal.Close(); err == nil {
+			err = journalErr
+		}
+		client.journal = nil
+	}
+	return err
+}
+func (client CfClient) Context() context.Context {
+	return client.providerCtx.Context()
+}
+func (client *CfClient) Compensate(f func(client Client) error) error {
+	err := client.Connect()
+	if err != nil {
+		return err
+	}
+	compensating, release := client.withProviderContext(detachedContext{client.providerCtx.Context()})
+	defer release()
+	return f(compensating)
+}
+func (client *CfClient) ForResource(address func() string, f func(client Client) error) error {
+	if client.journal == nil {
+		return f(client)
+	}
+	err := client.Connect()
+	if err != nil {
+		return err
+	}
+	resourceClient, release := client.withProviderContext(WithResourceAddress(client.providerCtx.Context(), address))
+	defer release()
+	return f(resourceClient)
+}
+
+// withProviderContext gives a copy of client whose requests use ctx, release must be called once copy is not used anymore
+func (client *CfClient) withProviderContext(ctx context.Context) (*CfClient, func()) {
+	c := *client
+	c.providerCtx = NewProviderContext(ctx)
+	c.httpClient = DetachHTTPClient(client.httpClient, c.providerCtx)
+	gwLogger, release := NewGatewayLogger(client.logger, c.httpClient)
+	c.gateways = NewCloudFoundryGateways(client.gateways.Config, gwLogger, client.uaaClient)
+	if client.ccv3Client != nil {
+		c.detachedCCv3 = new(detachedCCv3Client)
+	}
+	c.LoadRepositories()
+	c.LoadFinder()
+	return &c, release
+}
+
+// detachedCCv3Client is a ccv3 client using http client of a client copy, it targets cloud controller
+// only when copy uses it
+type detachedCCv3Client struct {
+	once       sync.Once
+	ccv3Client *ccv3.Client
+}
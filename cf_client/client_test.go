@@ -0,0 +1,51 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("CfClient", func() {
+	var server *httptest.Server
+	var calls int
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("should connect to Cloud Foundry only on first call to Connect", func() {
+		retry := common.DefaultRetryPolicy()
+		retry.MaxAttempts = 1
+		client, err := NewCfClient(context.Background(), Config{
+			ApiEndpoint:     server.URL,
+			UserAccessToken: "bearer token",
+			Retry:           retry,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(0))
+
+		err = client.Connect()
+		Expect(err).To(HaveOccurred())
+		callsOnConnect := calls
+		Expect(callsOnConnect).ToNot(BeZero())
+
+		Expect(client.Connect()).To(Equal(err))
+		Expect(calls).To(Equal(callsOnConnect))
+	})
+	It("should give an error on connect when no api endpoint is given", func() {
+		client, err := NewCfClient(context.Background(), Config{UserAccessToken: "bearer token"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Connect()).ToNot(Succeed())
+	})
+})
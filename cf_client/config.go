@@ -1,18 +1,47 @@
 package cf_client
 
+import (
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"os"
+	"strings"
+)
+
 type Config struct {
-	AppName          string
-	AppVersion       string
-	ApiEndpoint      string
-	SkipInsecureSSL  bool
-	Username         string
-	Password         string
-	UserRefreshToken string
-	UserAccessToken  string
-	Locale           string
-	Verbose          bool
-	EncPrivateKey    string
-	Passphrase       string
+	AppName               string
+	AppVersion            string
+	ApiEndpoint           string
+	SkipInsecureSSL       bool
+	CACert                string
+	ClientCert            string
+	ClientKey             string
+	HTTPProxy             string
+	HTTPSProxy            string
+	NoProxy               string
+	Username              string
+	Password              string
+	UaaClientID           string
+	UaaClientSecret       string
+	Origin                string
+	UserRefreshToken      string
+	UserAccessToken       string
+	AccessTokenFile       string
+	RefreshTokenFile      string
+	Locale                string
+	Verbose               bool
+	TraceFile             string
+	JournalFile           string
+	EncPrivateKey         string
+	Passphrase            string
+	CliConfig             *CliConfig
+	Retry                 common.RetryPolicy
+	MaxRequestsPerSecond  float64
+	MaxConcurrentRequests int
+	CacheLookups          bool
+	DefaultOrg            string
+	DefaultSpace          string
+	ReadOnly              bool
+	NonAdmin              bool
+	Protected             ProtectedObjects
 }
 
 func (c *Config) SkipSSLValidation() bool {
@@ -29,6 +58,10 @@ func (c *Config) ClientSecret() string {
 	return c.Password
 }
 
+func (c *Config) IsClientCredentials() bool {
+	return c.UaaClientID != "" && c.UaaClientSecret != ""
+}
+
 func (c *Config) AccessToken() string {
 	return c.UserAccessToken
 }
@@ -38,3 +71,58 @@ func (c *Config) RefreshToken() string {
 func (c *Config) SetAccessToken(token string) {
 	c.UserAccessToken = token
 }
+
+// LoadTokenFiles reads access and refresh tokens from their files when given,
+// those files are read again each time a refresh is triggered.
+func (c *Config) LoadTokenFiles() error {
+	accessToken, err := readTokenFile(c.AccessTokenFile)
+	if err != nil {
+		return err
+	}
+	if accessToken != "" {
+		c.UserAccessToken = bearerToken(accessToken)
+	}
+	refreshToken, err := readTokenFile(c.RefreshTokenFile)
+	if err != nil {
+		return err
+	}
+	if refreshToken != "" {
+		c.UserRefreshToken = refreshToken
+	}
+	return nil
+}
+
+func (c *Config) hasCredentials() bool {
+	return c.UserAccessToken != "" || c.IsClientCredentials() || (c.Username != "" && c.Password != "")
+}
+
+// LoadCliConfig fills target and session from a cf cli config file when they were not given.
+// If path is empty the default cli config file is used only if it exists.
+func (c *Config) LoadCliConfig(path string) error {
+	mandatory := path != ""
+	if !mandatory {
+		path = DefaultCliConfigPath()
+	}
+	cliConfig, err := LoadCliConfig(path)
+	if err != nil {
+		if !mandatory && os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	jsonConfig := cliConfig.JSONConfig()
+	if c.ApiEndpoint == "" {
+		c.ApiEndpoint = jsonConfig.Target
+	}
+	if strings.TrimSuffix(c.ApiEndpoint, "/") != strings.TrimSuffix(jsonConfig.Target, "/") {
+		return nil
+	}
+	if c.hasCredentials() || jsonConfig.AccessToken == "" {
+		return nil
+	}
+	c.UserAccessToken = jsonConfig.AccessToken
+	c.UserRefreshToken = jsonConfig.RefreshToken
+	c.SkipInsecureSSL = c.SkipInsecureSSL || jsonConfig.SkipSSLValidation
+	c.CliConfig = cliConfig
+	return nil
+}
@@ -1,13 +1,100 @@
 package cf_client_test
 
 import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"encoding/json"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 )
 
 var _ = Describe("Config", func() {
+	var tmpDir string
+	var cliConfigPath string
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cf-config")
+		Expect(err).ToNot(HaveOccurred())
+		cliConfigPath = filepath.Join(tmpDir, "config.json")
+		b, _ := json.Marshal(map[string]interface{}{
+			"Target":       "https://api.my.cf.com",
+			"AccessToken":  "bearer access",
+			"RefreshToken": "refresh",
+			"SSLDisabled":  true,
+		})
+		Expect(ioutil.WriteFile(cliConfigPath, b, 0600)).To(Succeed())
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+	Describe("LoadCliConfig", func() {
+		It("should use target and session from cli config when nothing is given", func() {
+			config := Config{}
+			Expect(config.LoadCliConfig(cliConfigPath)).To(Succeed())
+			Expect(config.ApiEndpoint).To(Equal("https://api.my.cf.com"))
+			Expect(config.UserAccessToken).To(Equal("bearer access"))
+			Expect(config.UserRefreshToken).To(Equal("refresh"))
+			Expect(config.SkipInsecureSSL).To(BeTrue())
+			Expect(config.CliConfig).ToNot(BeNil())
+		})
+		It("should not use session from cli config when credentials are given", func() {
+			config := Config{Username: "user", Password: "password"}
+			Expect(config.LoadCliConfig(cliConfigPath)).To(Succeed())
+			Expect(config.UserAccessToken).To(BeEmpty())
+			Expect(config.CliConfig).To(BeNil())
+		})
+		It("should not use session from cli config when it targets another api", func() {
+			config := Config{ApiEndpoint: "https://api.other.cf.com"}
+			Expect(config.LoadCliConfig(cliConfigPath)).To(Succeed())
+			Expect(config.ApiEndpoint).To(Equal("https://api.other.cf.com"))
+			Expect(config.UserAccessToken).To(BeEmpty())
+		})
+		It("should return an error when given cli config file doesn't exist", func() {
+			config := Config{}
+			Expect(config.LoadCliConfig(filepath.Join(tmpDir, "notfound.json"))).ToNot(Succeed())
+		})
+	})
+	Describe("CliConfig", func() {
+		It("should write back refreshed tokens", func() {
+			cliConfig, err := LoadCliConfig(cliConfigPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cliConfig.SaveTokens("bearer newaccess", "newrefresh")).To(Succeed())
 
-	It("should work", func() {
+			cliConfig, err = LoadCliConfig(cliConfigPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cliConfig.JSONConfig().AccessToken).To(Equal("bearer newaccess"))
+			Expect(cliConfig.JSONConfig().RefreshToken).To(Equal("newrefresh"))
+			Expect(cliConfig.JSONConfig().Target).To(Equal("https://api.my.cf.com"))
+		})
+		It("should only replace tokens in file changed since it was loaded", func() {
+			cliConfig, err := LoadCliConfig(cliConfigPath)
+			Expect(err).ToNot(HaveOccurred())
+			b, _ := json.Marshal(map[string]interface{}{
+				"Target":       "https://api.other.cf.com",
+				"AccessToken":  "bearer access",
+				"RefreshToken": "refresh",
+				"UnknownField": map[string]interface{}{"key": "value"},
+			})
+			Expect(ioutil.WriteFile(cliConfigPath, b, 0600)).To(Succeed())
 
-	})
+			Expect(cliConfig.SaveTokens("bearer newaccess", "newrefresh")).To(Succeed())
 
+			b, err = ioutil.ReadFile(cliConfigPath)
+			Expect(err).ToNot(HaveOccurred())
+			var content map[string]interface{}
+			Expect(json.Unmarshal(b, &content)).To(Succeed())
+			Expect(content).To(Equal(map[string]interface{}{
+				"Target":       "https://api.other.cf.com",
+				"AccessToken":  "bearer newaccess",
+				"RefreshToken": "newrefresh",
+				"UnknownField": map[string]interface{}{"key": "value"},
+			}))
+			files, err := ioutil.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+		})
+	})
 })
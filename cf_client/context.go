@@ -0,0 +1,84 @@
+package cf_client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ProviderContext is canceled when terraform is interrupted, requests sent without their own context
+// are aborted when it is canceled.
+type ProviderContext struct {
+	ctx context.Context
+}
+
+func NewProviderContext(ctx context.Context) ProviderContext {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return ProviderContext{
+		ctx: ctx,
+	}
+}
+func (c ProviderContext) Context() context.Context {
+	return c.ctx
+}
+
+// ContextTransport makes requests sent without their own context (e.g.: by cf/net gateways) use provider context
+type ContextTransport struct {
+	providerCtx ProviderContext
+	transport   http.RoundTripper
+}
+
+func NewContextTransport(providerCtx ProviderContext, transport http.RoundTripper) *ContextTransport {
+	return &ContextTransport{
+		providerCtx: providerCtx,
+		transport:   transport,
+	}
+}
+func (t *ContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context() == context.Background() {
+		req = req.WithContext(t.providerCtx.Context())
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// DetachHTTPClient gives a copy of httpClient which uses providerCtx instead of its own provider context,
+// other transports (e.g.: cache, journal and rate limiter) stay shared with httpClient.
+func DetachHTTPClient(httpClient *http.Client, providerCtx ProviderContext) *http.Client {
+	detached := *httpClient
+	if t, ok := httpClient.Transport.(*ContextTransport); ok {
+		detached.Transport = NewContextTransport(providerCtx, t.transport)
+	}
+	return &detached
+}
+
+type resourceAddressKey struct{}
+
+// WithResourceAddress gives a copy of ctx whose requests are recorded in journal with address of the resource doing them,
+// address is called when a request is recorded (e.g.: guid of an app is only known once it is created).
+func WithResourceAddress(ctx context.Context, address func() string) context.Context {
+	return context.WithValue(ctx, resourceAddressKey{}, address)
+}
+func resourceAddressFromContext(ctx context.Context) string {
+	address, ok := ctx.Value(resourceAddressKey{}).(func() string)
+	if !ok {
+		return ""
+	}
+	return address()
+}
+
+// detachedContext keeps values of a context (e.g.: resource address) without being canceled with it
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+func (detachedContext) Err() error {
+	return nil
+}
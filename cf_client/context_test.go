@@ -0,0 +1,58 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("ContextTransport", func() {
+	var server *httptest.Server
+	var calls int
+	var client *http.Client
+	var providerCtx ProviderContext
+	var cancel context.CancelFunc
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Write([]byte(`{}`))
+		}))
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		providerCtx = NewProviderContext(ctx)
+		client = &http.Client{Transport: NewContextTransport(providerCtx, http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+	It("should send requests while provider context is not canceled", func() {
+		resp, err := client.Get(server.URL + "/v2/apps/guid")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(calls).To(Equal(1))
+	})
+	It("should abort requests when provider context is canceled", func() {
+		cancel()
+		_, err := client.Get(server.URL + "/v2/apps/guid")
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(0))
+	})
+	It("should still send requests of a detached http client", func() {
+		cancel()
+		detached := DetachHTTPClient(client, NewProviderContext(context.Background()))
+		resp, err := detached.Get(server.URL + "/v2/apps/guid")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(calls).To(Equal(1))
+
+		_, err = client.Get(server.URL + "/v2/apps/guid")
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+})
@@ -0,0 +1,158 @@
+package cf_client
+
+import (
+	"bytes"
+	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
+	"code.cloudfoundry.org/cli/cf/net"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	DeploymentStateDeployed = "DEPLOYED"
+	DeploymentStateCanceled = "CANCELED"
+	DeploymentStateFailed   = "FAILED"
+
+	DeploymentStatusFinalized = "FINALIZED"
+)
+
+// Deployment is a cloud controller v3 deployment replacing instances of an app one by one
+// with instances running another droplet.
+type Deployment struct {
+	GUID        string
+	AppGUID     string
+	DropletGUID string
+	// State is only given by cloud controller before status was introduced (e.g.: DEPLOYING, DEPLOYED, CANCELED)
+	State string
+	// StatusValue is ACTIVE (or DEPLOYING and CANCELING on first versions) until deployment is FINALIZED
+	StatusValue string
+	// StatusReason tells why deployment is finalized (e.g.: DEPLOYED, CANCELED, SUPERSEDED)
+	StatusReason string
+}
+
+// IsFinished is true when cloud controller doesn't replace instances anymore
+func (d Deployment) IsFinished() bool {
+	if d.StatusValue != "" {
+		return d.StatusValue == DeploymentStatusFinalized
+	}
+	return d.State == DeploymentStateDeployed || d.State == DeploymentStateCanceled || d.State == DeploymentStateFailed
+}
+
+// IsDeployed is true when every instance runs the new droplet
+func (d Deployment) IsDeployed() bool {
+	if d.StatusValue != "" {
+		return d.StatusValue == DeploymentStatusFinalized && d.StatusReason == DeploymentStateDeployed
+	}
+	return d.State == DeploymentStateDeployed
+}
+
+// Status gives state or status of deployment as shown by cloud controller
+func (d Deployment) Status() string {
+	if d.StatusValue == "" {
+		return d.State
+	}
+	if d.StatusReason == "" {
+		return d.StatusValue
+	}
+	return fmt.Sprintf("%s (%s)", d.StatusValue, d.StatusReason)
+}
+
+type v3Deployment struct {
+	GUID   string `json:"guid"`
+	State  string `json:"state"`
+	Status *struct {
+		Value  string `json:"value"`
+		Reason string `json:"reason"`
+	} `json:"status"`
+	Droplet struct {
+		GUID string `json:"guid"`
+	} `json:"droplet"`
+	Relationships struct {
+		App v3Relationship `json:"app"`
+	} `json:"relationships"`
+}
+
+func (d v3Deployment) ToDeployment() Deployment {
+	deployment := Deployment{
+		GUID:        d.GUID,
+		AppGUID:     d.Relationships.App.GUID(),
+		DropletGUID: d.Droplet.GUID,
+		State:       d.State,
+	}
+	if d.Status != nil {
+		deployment.StatusValue = d.Status.Value
+		deployment.StatusReason = d.Status.Reason
+	}
+	return deployment
+}
+
+// DeploymentRepository manages cloud controller v3 deployments,
+// the ccv3 client vendored doesn't support them yet.
+type DeploymentRepository interface {
+	Create(appGuid, dropletGuid string) (Deployment, error)
+	Get(deploymentGuid string) (Deployment, error)
+	Cancel(deploymentGuid string) error
+}
+
+type CloudControllerDeploymentRepository struct {
+	config    coreconfig.Reader
+	ccGateway net.Gateway
+}
+
+func NewCloudControllerDeploymentRepository(config coreconfig.Reader, ccGateway net.Gateway) *CloudControllerDeploymentRepository {
+	return &CloudControllerDeploymentRepository{
+		config:    config,
+		ccGateway: ccGateway,
+	}
+}
+
+// Create starts a rolling deployment of droplet, app current droplet is used when dropletGuid is empty
+func (r CloudControllerDeploymentRepository) Create(appGuid, dropletGuid string) (Deployment, error) {
+	body := map[string]interface{}{
+		"relationships": map[string]interface{}{
+			"app": map[string]interface{}{
+				"data": map[string]string{"guid": appGuid},
+			},
+		},
+	}
+	if dropletGuid != "" {
+		body["droplet"] = map[string]string{"guid": dropletGuid}
+	}
+	deployment := v3Deployment{}
+	err := sendV3Request(r.config, r.ccGateway, "POST", "/v3/deployments", body, &deployment)
+	if err != nil {
+		return Deployment{}, err
+	}
+	return deployment.ToDeployment(), nil
+}
+func (r CloudControllerDeploymentRepository) Get(deploymentGuid string) (Deployment, error) {
+	deployment := v3Deployment{}
+	err := r.ccGateway.GetResource(fmt.Sprintf("%s/v3/deployments/%s", r.config.APIEndpoint(), deploymentGuid), &deployment)
+	if err != nil {
+		return Deployment{}, err
+	}
+	return deployment.ToDeployment(), nil
+}
+
+// Cancel stops deployment and rolls back instances already replaced to the previous droplet
+func (r CloudControllerDeploymentRepository) Cancel(deploymentGuid string) error {
+	return sendV3Request(r.config, r.ccGateway, "POST", fmt.Sprintf("/v3/deployments/%s/actions/cancel", deploymentGuid), nil, &v3Deployment{})
+}
+
+// sendV3Request sends a v3 request without the async query added by gateway on creation
+func sendV3Request(config coreconfig.Reader, ccGateway net.Gateway, method, path string, body interface{}, resource interface{}) error {
+	data := []byte{}
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	request, err := ccGateway.NewRequest(method, config.APIEndpoint()+path, config.AccessToken(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	_, err = ccGateway.PerformRequestForJSONResponse(request, resource)
+	return err
+}
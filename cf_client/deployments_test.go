@@ -0,0 +1,39 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deployment", func() {
+	Context("when cloud controller gives a status", func() {
+		It("should be finished and deployed only when finalized as deployed", func() {
+			deployment := Deployment{StatusValue: DeploymentStatusFinalized, StatusReason: DeploymentStateDeployed}
+			Expect(deployment.IsFinished()).To(BeTrue())
+			Expect(deployment.IsDeployed()).To(BeTrue())
+			Expect(deployment.Status()).To(Equal("FINALIZED (DEPLOYED)"))
+		})
+		It("should be finished but not deployed when finalized as canceled", func() {
+			deployment := Deployment{StatusValue: DeploymentStatusFinalized, StatusReason: DeploymentStateCanceled}
+			Expect(deployment.IsFinished()).To(BeTrue())
+			Expect(deployment.IsDeployed()).To(BeFalse())
+		})
+		It("should not be finished while active", func() {
+			deployment := Deployment{State: "DEPLOYING", StatusValue: "ACTIVE"}
+			Expect(deployment.IsFinished()).To(BeFalse())
+			Expect(deployment.IsDeployed()).To(BeFalse())
+			Expect(deployment.Status()).To(Equal("ACTIVE"))
+		})
+	})
+	Context("when cloud controller only gives a state", func() {
+		It("should use state to know if deployment is finished", func() {
+			Expect(Deployment{State: "DEPLOYING"}.IsFinished()).To(BeFalse())
+			Expect(Deployment{State: DeploymentStateFailed}.IsFinished()).To(BeTrue())
+			Expect(Deployment{State: DeploymentStateFailed}.IsDeployed()).To(BeFalse())
+			Expect(Deployment{State: DeploymentStateDeployed}.IsDeployed()).To(BeTrue())
+			Expect(Deployment{State: DeploymentStateCanceled}.Status()).To(Equal("CANCELED"))
+		})
+	})
+})
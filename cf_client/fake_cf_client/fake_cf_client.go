@@ -5,7 +5,9 @@ import (
 	"code.cloudfoundry.org/cli/cf/api"
 	"code.cloudfoundry.org/cli/cf/api/apifakes"
 	"code.cloudfoundry.org/cli/cf/api/appinstances"
+	"code.cloudfoundry.org/cli/cf/api/appinstances/appinstancesfakes"
 	"code.cloudfoundry.org/cli/cf/api/applications"
+	"code.cloudfoundry.org/cli/cf/api/applications/applicationsfakes"
 	"code.cloudfoundry.org/cli/cf/api/environmentvariablegroups"
 	"code.cloudfoundry.org/cli/cf/api/featureflags"
 	"code.cloudfoundry.org/cli/cf/api/logs"
@@ -26,11 +28,15 @@ import (
 	"code.cloudfoundry.org/cli/cf/api/spaces"
 	"code.cloudfoundry.org/cli/cf/api/spaces/spacesfakes"
 	"code.cloudfoundry.org/cli/cf/api/stacks"
+	"code.cloudfoundry.org/cli/cf/models"
+	"context"
+	"crypto/tls"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager/bitsmanagerfakes"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/encryption"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/encryption/fake_encryption"
+	"net/http"
 )
 
 type FakeCfClient struct {
@@ -58,6 +64,14 @@ type FakeCfClient struct {
 	userProvidedService         *apifakes.FakeUserProvidedServiceInstanceRepository
 	finder                      *FakeFinderRepository
 	applicationBits             *bitsmanagerfakes.FakeApplicationBitsRepository
+	deployments                 *FakeDeploymentRepository
+	processes                   *FakeProcessRepository
+	sidecars                    *FakeSidecarRepository
+	applications                *applicationsfakes.FakeRepository
+	appInstances                *appinstancesfakes.FakeRepository
+	logs                        *FakeLogsRepository
+	ctx                         context.Context
+	compensateCallCount         int
 }
 
 func NewFakeCfClient() *FakeCfClient {
@@ -68,6 +82,25 @@ func NewFakeCfClient() *FakeCfClient {
 func (c *FakeCfClient) GetClient() cf_client.Client {
 	return c
 }
+func (c *FakeCfClient) Connect() error {
+	return nil
+}
+func (c *FakeCfClient) Context() context.Context {
+	return c.ctx
+}
+func (c *FakeCfClient) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+func (c *FakeCfClient) Compensate(f func(client cf_client.Client) error) error {
+	c.compensateCallCount++
+	return f(c)
+}
+func (c *FakeCfClient) CompensateCallCount() int {
+	return c.compensateCallCount
+}
+func (c *FakeCfClient) ForResource(address func() string, f func(client cf_client.Client) error) error {
+	return f(c)
+}
 func (c *FakeCfClient) Init() {
 	c.config = cf_client.Config{
 		ApiEndpoint: "http://fake.api.endpoint.com",
@@ -94,6 +127,13 @@ func (c *FakeCfClient) Init() {
 	c.userProvidedService = new(apifakes.FakeUserProvidedServiceInstanceRepository)
 	c.applicationBits = new(bitsmanagerfakes.FakeApplicationBitsRepository)
 	c.finder = new(FakeFinderRepository)
+	c.deployments = new(FakeDeploymentRepository)
+	c.processes = new(FakeProcessRepository)
+	c.sidecars = new(FakeSidecarRepository)
+	c.applications = new(applicationsfakes.FakeRepository)
+	c.appInstances = new(appinstancesfakes.FakeRepository)
+	c.logs = new(FakeLogsRepository)
+	c.ctx = context.Background()
 	c.decrypter = fake_encryption.NewFakeDecrypter()
 }
 func (client FakeCfClient) Organizations() organizations.OrganizationRepository {
@@ -123,6 +163,9 @@ func (client FakeCfClient) Quotas() quotas.QuotaRepository {
 func (client FakeCfClient) Config() cf_client.Config {
 	return client.config
 }
+func (c *FakeCfClient) SetProtected(protected cf_client.ProtectedObjects) {
+	c.config.Protected = protected
+}
 func (client FakeCfClient) Buildpack() api.BuildpackRepository {
 	return client.buildpack
 }
@@ -185,16 +228,55 @@ func (client FakeCfClient) CCv3Client() *ccv3.Client {
 	return &ccv3.Client{}
 }
 func (client FakeCfClient) Applications() applications.Repository {
-	return applications.CloudControllerRepository{}
+	return client.applications
 }
 func (client FakeCfClient) AppInstances() appinstances.Repository {
-	return appinstances.CloudControllerAppInstancesRepository{}
+	return client.appInstances
 }
 func (client FakeCfClient) ApplicationBits() bitsmanager.ApplicationBitsRepository {
 	return client.applicationBits
 }
+func (client FakeCfClient) Deployments() cf_client.DeploymentRepository {
+	return client.deployments
+}
+func (client FakeCfClient) Processes() cf_client.ProcessRepository {
+	return client.processes
+}
+func (client FakeCfClient) Sidecars() cf_client.SidecarRepository {
+	return client.sidecars
+}
 func (client FakeCfClient) Logs() logs.Repository {
-	return &logs.NoaaLogsRepository{}
+	return client.logs
+}
+func (client FakeCfClient) HTTPClient() *http.Client {
+	return http.DefaultClient
+}
+func (client FakeCfClient) TLSConfig() *tls.Config {
+	return &tls.Config{}
+}
+func (client FakeCfClient) Proxy() cf_client.ProxyFunc {
+	return http.ProxyFromEnvironment
+}
+func (client FakeCfClient) Capabilities() cf_client.Capabilities {
+	return cf_client.Capabilities{
+		V2Version:              "99.0.0",
+		V3Version:              "99.0.0",
+		V3Available:            true,
+		RoutingAPIAvailable:    true,
+		NetworkPolicyAvailable: true,
+	}
+}
+func (client FakeCfClient) DefaultOrg() models.OrganizationFields {
+	return models.OrganizationFields{}
+}
+func (client FakeCfClient) DefaultSpace() models.SpaceFields {
+	return models.SpaceFields{}
+}
+func (client FakeCfClient) Journal() *cf_client.MutationJournal {
+	return nil
+}
+func (client FakeCfClient) Close() error {
+	return nil
 }
 
 // get Fake call -------
@@ -268,3 +350,21 @@ func (client FakeCfClient) FakeFinder() *FakeFinderRepository {
 func (client FakeCfClient) FakeApplicationBits() *bitsmanagerfakes.FakeApplicationBitsRepository {
 	return client.applicationBits
 }
+func (client FakeCfClient) FakeDeployments() *FakeDeploymentRepository {
+	return client.deployments
+}
+func (client FakeCfClient) FakeProcesses() *FakeProcessRepository {
+	return client.processes
+}
+func (client FakeCfClient) FakeSidecars() *FakeSidecarRepository {
+	return client.sidecars
+}
+func (client FakeCfClient) FakeApplications() *applicationsfakes.FakeRepository {
+	return client.applications
+}
+func (client FakeCfClient) FakeAppInstances() *appinstancesfakes.FakeRepository {
+	return client.appInstances
+}
+func (client FakeCfClient) FakeLogs() *FakeLogsRepository {
+	return client.logs
+}
@@ -2,6 +2,7 @@
 package fake_cf_client
 
 import (
+	"context"
 	"sync"
 
 	"code.cloudfoundry.org/cli/cf/models"
@@ -9,9 +10,10 @@ import (
 )
 
 type FakeFinderRepository struct {
-	GetDomainFromCfStub        func(domain models.DomainFields) (models.DomainFields, error)
+	GetDomainFromCfStub        func(ctx context.Context, domain models.DomainFields) (models.DomainFields, error)
 	getDomainFromCfMutex       sync.RWMutex
 	getDomainFromCfArgsForCall []struct {
+		ctx    context.Context
 		domain models.DomainFields
 	}
 	getDomainFromCfReturns struct {
@@ -22,9 +24,10 @@ type FakeFinderRepository struct {
 		result1 models.DomainFields
 		result2 error
 	}
-	GetBuildpackFromCfStub        func(bpGuid string) (models.Buildpack, error)
+	GetBuildpackFromCfStub        func(ctx context.Context, bpGuid string) (models.Buildpack, error)
 	getBuildpackFromCfMutex       sync.RWMutex
 	getBuildpackFromCfArgsForCall []struct {
+		ctx    context.Context
 		bpGuid string
 	}
 	getBuildpackFromCfReturns struct {
@@ -35,9 +38,10 @@ type FakeFinderRepository struct {
 		result1 models.Buildpack
 		result2 error
 	}
-	GetQuotaFromCfStub        func(quotaGuid string, isOrgQuota bool) (interface{}, error)
+	GetQuotaFromCfStub        func(ctx context.Context, quotaGuid string, isOrgQuota bool) (interface{}, error)
 	getQuotaFromCfMutex       sync.RWMutex
 	getQuotaFromCfArgsForCall []struct {
+		ctx        context.Context
 		quotaGuid  string
 		isOrgQuota bool
 	}
@@ -49,9 +53,10 @@ type FakeFinderRepository struct {
 		result1 interface{}
 		result2 error
 	}
-	GetRouteFromCfStub        func(routeGuid string) (models.Route, error)
+	GetRouteFromCfStub        func(ctx context.Context, routeGuid string) (models.Route, error)
 	getRouteFromCfMutex       sync.RWMutex
 	getRouteFromCfArgsForCall []struct {
+		ctx       context.Context
 		routeGuid string
 	}
 	getRouteFromCfReturns struct {
@@ -62,9 +67,10 @@ type FakeFinderRepository struct {
 		result1 models.Route
 		result2 error
 	}
-	GetSecGroupFromCfStub        func(secGroupId string) (models.SecurityGroup, error)
+	GetSecGroupFromCfStub        func(ctx context.Context, secGroupId string) (models.SecurityGroup, error)
 	getSecGroupFromCfMutex       sync.RWMutex
 	getSecGroupFromCfArgsForCall []struct {
+		ctx        context.Context
 		secGroupId string
 	}
 	getSecGroupFromCfReturns struct {
@@ -75,9 +81,10 @@ type FakeFinderRepository struct {
 		result1 models.SecurityGroup
 		result2 error
 	}
-	GetServiceFromCfStub        func(svcGuid string) (models.ServiceInstance, error)
+	GetServiceFromCfStub        func(ctx context.Context, svcGuid string) (models.ServiceInstance, error)
 	getServiceFromCfMutex       sync.RWMutex
 	getServiceFromCfArgsForCall []struct {
+		ctx     context.Context
 		svcGuid string
 	}
 	getServiceFromCfReturns struct {
@@ -88,9 +95,10 @@ type FakeFinderRepository struct {
 		result1 models.ServiceInstance
 		result2 error
 	}
-	GetSpaceFromCfStub        func(spaceGuid string) (models.Space, error)
+	GetSpaceFromCfStub        func(ctx context.Context, spaceGuid string) (models.Space, error)
 	getSpaceFromCfMutex       sync.RWMutex
 	getSpaceFromCfArgsForCall []struct {
+		ctx       context.Context
 		spaceGuid string
 	}
 	getSpaceFromCfReturns struct {
@@ -101,9 +109,10 @@ type FakeFinderRepository struct {
 		result1 models.Space
 		result2 error
 	}
-	GetAppFromCfStub        func(appGuid string) (models.Application, error)
+	GetAppFromCfStub        func(ctx context.Context, appGuid string) (models.Application, error)
 	getAppFromCfMutex       sync.RWMutex
 	getAppFromCfArgsForCall []struct {
+		ctx     context.Context
 		appGuid string
 	}
 	getAppFromCfReturns struct {
@@ -114,9 +123,10 @@ type FakeFinderRepository struct {
 		result1 models.Application
 		result2 error
 	}
-	GetServiceBindingsFromAppStub        func(appGuid string) ([]cf_client.ServiceBindingFields, error)
+	GetServiceBindingsFromAppStub        func(ctx context.Context, appGuid string) ([]cf_client.ServiceBindingFields, error)
 	getServiceBindingsFromAppMutex       sync.RWMutex
 	getServiceBindingsFromAppArgsForCall []struct {
+		ctx     context.Context
 		appGuid string
 	}
 	getServiceBindingsFromAppReturns struct {
@@ -131,16 +141,17 @@ type FakeFinderRepository struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeFinderRepository) GetDomainFromCf(domain models.DomainFields) (models.DomainFields, error) {
+func (fake *FakeFinderRepository) GetDomainFromCf(ctx context.Context, domain models.DomainFields) (models.DomainFields, error) {
 	fake.getDomainFromCfMutex.Lock()
 	ret, specificReturn := fake.getDomainFromCfReturnsOnCall[len(fake.getDomainFromCfArgsForCall)]
 	fake.getDomainFromCfArgsForCall = append(fake.getDomainFromCfArgsForCall, struct {
+		ctx    context.Context
 		domain models.DomainFields
-	}{domain})
-	fake.recordInvocation("GetDomainFromCf", []interface{}{domain})
+	}{ctx, domain})
+	fake.recordInvocation("GetDomainFromCf", []interface{}{ctx, domain})
 	fake.getDomainFromCfMutex.Unlock()
 	if fake.GetDomainFromCfStub != nil {
-		return fake.GetDomainFromCfStub(domain)
+		return fake.GetDomainFromCfStub(ctx, domain)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -154,10 +165,10 @@ func (fake *FakeFinderRepository) GetDomainFromCfCallCount() int {
 	return len(fake.getDomainFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetDomainFromCfArgsForCall(i int) models.DomainFields {
+func (fake *FakeFinderRepository) GetDomainFromCfArgsForCall(i int) (context.Context, models.DomainFields) {
 	fake.getDomainFromCfMutex.RLock()
 	defer fake.getDomainFromCfMutex.RUnlock()
-	return fake.getDomainFromCfArgsForCall[i].domain
+	return fake.getDomainFromCfArgsForCall[i].ctx, fake.getDomainFromCfArgsForCall[i].domain
 }
 
 func (fake *FakeFinderRepository) GetDomainFromCfReturns(result1 models.DomainFields, result2 error) {
@@ -182,16 +193,17 @@ func (fake *FakeFinderRepository) GetDomainFromCfReturnsOnCall(i int, result1 mo
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetBuildpackFromCf(bpGuid string) (models.Buildpack, error) {
+func (fake *FakeFinderRepository) GetBuildpackFromCf(ctx context.Context, bpGuid string) (models.Buildpack, error) {
 	fake.getBuildpackFromCfMutex.Lock()
 	ret, specificReturn := fake.getBuildpackFromCfReturnsOnCall[len(fake.getBuildpackFromCfArgsForCall)]
 	fake.getBuildpackFromCfArgsForCall = append(fake.getBuildpackFromCfArgsForCall, struct {
+		ctx    context.Context
 		bpGuid string
-	}{bpGuid})
-	fake.recordInvocation("GetBuildpackFromCf", []interface{}{bpGuid})
+	}{ctx, bpGuid})
+	fake.recordInvocation("GetBuildpackFromCf", []interface{}{ctx, bpGuid})
 	fake.getBuildpackFromCfMutex.Unlock()
 	if fake.GetBuildpackFromCfStub != nil {
-		return fake.GetBuildpackFromCfStub(bpGuid)
+		return fake.GetBuildpackFromCfStub(ctx, bpGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -205,10 +217,10 @@ func (fake *FakeFinderRepository) GetBuildpackFromCfCallCount() int {
 	return len(fake.getBuildpackFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetBuildpackFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetBuildpackFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getBuildpackFromCfMutex.RLock()
 	defer fake.getBuildpackFromCfMutex.RUnlock()
-	return fake.getBuildpackFromCfArgsForCall[i].bpGuid
+	return fake.getBuildpackFromCfArgsForCall[i].ctx, fake.getBuildpackFromCfArgsForCall[i].bpGuid
 }
 
 func (fake *FakeFinderRepository) GetBuildpackFromCfReturns(result1 models.Buildpack, result2 error) {
@@ -233,17 +245,18 @@ func (fake *FakeFinderRepository) GetBuildpackFromCfReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetQuotaFromCf(quotaGuid string, isOrgQuota bool) (interface{}, error) {
+func (fake *FakeFinderRepository) GetQuotaFromCf(ctx context.Context, quotaGuid string, isOrgQuota bool) (interface{}, error) {
 	fake.getQuotaFromCfMutex.Lock()
 	ret, specificReturn := fake.getQuotaFromCfReturnsOnCall[len(fake.getQuotaFromCfArgsForCall)]
 	fake.getQuotaFromCfArgsForCall = append(fake.getQuotaFromCfArgsForCall, struct {
+		ctx        context.Context
 		quotaGuid  string
 		isOrgQuota bool
-	}{quotaGuid, isOrgQuota})
-	fake.recordInvocation("GetQuotaFromCf", []interface{}{quotaGuid, isOrgQuota})
+	}{ctx, quotaGuid, isOrgQuota})
+	fake.recordInvocation("GetQuotaFromCf", []interface{}{ctx, quotaGuid, isOrgQuota})
 	fake.getQuotaFromCfMutex.Unlock()
 	if fake.GetQuotaFromCfStub != nil {
-		return fake.GetQuotaFromCfStub(quotaGuid, isOrgQuota)
+		return fake.GetQuotaFromCfStub(ctx, quotaGuid, isOrgQuota)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -257,10 +270,10 @@ func (fake *FakeFinderRepository) GetQuotaFromCfCallCount() int {
 	return len(fake.getQuotaFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetQuotaFromCfArgsForCall(i int) (string, bool) {
+func (fake *FakeFinderRepository) GetQuotaFromCfArgsForCall(i int) (context.Context, string, bool) {
 	fake.getQuotaFromCfMutex.RLock()
 	defer fake.getQuotaFromCfMutex.RUnlock()
-	return fake.getQuotaFromCfArgsForCall[i].quotaGuid, fake.getQuotaFromCfArgsForCall[i].isOrgQuota
+	return fake.getQuotaFromCfArgsForCall[i].ctx, fake.getQuotaFromCfArgsForCall[i].quotaGuid, fake.getQuotaFromCfArgsForCall[i].isOrgQuota
 }
 
 func (fake *FakeFinderRepository) GetQuotaFromCfReturns(result1 interface{}, result2 error) {
@@ -285,16 +298,17 @@ func (fake *FakeFinderRepository) GetQuotaFromCfReturnsOnCall(i int, result1 int
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetRouteFromCf(routeGuid string) (models.Route, error) {
+func (fake *FakeFinderRepository) GetRouteFromCf(ctx context.Context, routeGuid string) (models.Route, error) {
 	fake.getRouteFromCfMutex.Lock()
 	ret, specificReturn := fake.getRouteFromCfReturnsOnCall[len(fake.getRouteFromCfArgsForCall)]
 	fake.getRouteFromCfArgsForCall = append(fake.getRouteFromCfArgsForCall, struct {
+		ctx       context.Context
 		routeGuid string
-	}{routeGuid})
-	fake.recordInvocation("GetRouteFromCf", []interface{}{routeGuid})
+	}{ctx, routeGuid})
+	fake.recordInvocation("GetRouteFromCf", []interface{}{ctx, routeGuid})
 	fake.getRouteFromCfMutex.Unlock()
 	if fake.GetRouteFromCfStub != nil {
-		return fake.GetRouteFromCfStub(routeGuid)
+		return fake.GetRouteFromCfStub(ctx, routeGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -308,10 +322,10 @@ func (fake *FakeFinderRepository) GetRouteFromCfCallCount() int {
 	return len(fake.getRouteFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetRouteFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetRouteFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getRouteFromCfMutex.RLock()
 	defer fake.getRouteFromCfMutex.RUnlock()
-	return fake.getRouteFromCfArgsForCall[i].routeGuid
+	return fake.getRouteFromCfArgsForCall[i].ctx, fake.getRouteFromCfArgsForCall[i].routeGuid
 }
 
 func (fake *FakeFinderRepository) GetRouteFromCfReturns(result1 models.Route, result2 error) {
@@ -336,16 +350,17 @@ func (fake *FakeFinderRepository) GetRouteFromCfReturnsOnCall(i int, result1 mod
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetSecGroupFromCf(secGroupId string) (models.SecurityGroup, error) {
+func (fake *FakeFinderRepository) GetSecGroupFromCf(ctx context.Context, secGroupId string) (models.SecurityGroup, error) {
 	fake.getSecGroupFromCfMutex.Lock()
 	ret, specificReturn := fake.getSecGroupFromCfReturnsOnCall[len(fake.getSecGroupFromCfArgsForCall)]
 	fake.getSecGroupFromCfArgsForCall = append(fake.getSecGroupFromCfArgsForCall, struct {
+		ctx        context.Context
 		secGroupId string
-	}{secGroupId})
-	fake.recordInvocation("GetSecGroupFromCf", []interface{}{secGroupId})
+	}{ctx, secGroupId})
+	fake.recordInvocation("GetSecGroupFromCf", []interface{}{ctx, secGroupId})
 	fake.getSecGroupFromCfMutex.Unlock()
 	if fake.GetSecGroupFromCfStub != nil {
-		return fake.GetSecGroupFromCfStub(secGroupId)
+		return fake.GetSecGroupFromCfStub(ctx, secGroupId)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -359,10 +374,10 @@ func (fake *FakeFinderRepository) GetSecGroupFromCfCallCount() int {
 	return len(fake.getSecGroupFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetSecGroupFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetSecGroupFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getSecGroupFromCfMutex.RLock()
 	defer fake.getSecGroupFromCfMutex.RUnlock()
-	return fake.getSecGroupFromCfArgsForCall[i].secGroupId
+	return fake.getSecGroupFromCfArgsForCall[i].ctx, fake.getSecGroupFromCfArgsForCall[i].secGroupId
 }
 
 func (fake *FakeFinderRepository) GetSecGroupFromCfReturns(result1 models.SecurityGroup, result2 error) {
@@ -387,16 +402,17 @@ func (fake *FakeFinderRepository) GetSecGroupFromCfReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetServiceFromCf(svcGuid string) (models.ServiceInstance, error) {
+func (fake *FakeFinderRepository) GetServiceFromCf(ctx context.Context, svcGuid string) (models.ServiceInstance, error) {
 	fake.getServiceFromCfMutex.Lock()
 	ret, specificReturn := fake.getServiceFromCfReturnsOnCall[len(fake.getServiceFromCfArgsForCall)]
 	fake.getServiceFromCfArgsForCall = append(fake.getServiceFromCfArgsForCall, struct {
+		ctx     context.Context
 		svcGuid string
-	}{svcGuid})
-	fake.recordInvocation("GetServiceFromCf", []interface{}{svcGuid})
+	}{ctx, svcGuid})
+	fake.recordInvocation("GetServiceFromCf", []interface{}{ctx, svcGuid})
 	fake.getServiceFromCfMutex.Unlock()
 	if fake.GetServiceFromCfStub != nil {
-		return fake.GetServiceFromCfStub(svcGuid)
+		return fake.GetServiceFromCfStub(ctx, svcGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -410,10 +426,10 @@ func (fake *FakeFinderRepository) GetServiceFromCfCallCount() int {
 	return len(fake.getServiceFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetServiceFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetServiceFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getServiceFromCfMutex.RLock()
 	defer fake.getServiceFromCfMutex.RUnlock()
-	return fake.getServiceFromCfArgsForCall[i].svcGuid
+	return fake.getServiceFromCfArgsForCall[i].ctx, fake.getServiceFromCfArgsForCall[i].svcGuid
 }
 
 func (fake *FakeFinderRepository) GetServiceFromCfReturns(result1 models.ServiceInstance, result2 error) {
@@ -438,16 +454,17 @@ func (fake *FakeFinderRepository) GetServiceFromCfReturnsOnCall(i int, result1 m
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetSpaceFromCf(spaceGuid string) (models.Space, error) {
+func (fake *FakeFinderRepository) GetSpaceFromCf(ctx context.Context, spaceGuid string) (models.Space, error) {
 	fake.getSpaceFromCfMutex.Lock()
 	ret, specificReturn := fake.getSpaceFromCfReturnsOnCall[len(fake.getSpaceFromCfArgsForCall)]
 	fake.getSpaceFromCfArgsForCall = append(fake.getSpaceFromCfArgsForCall, struct {
+		ctx       context.Context
 		spaceGuid string
-	}{spaceGuid})
-	fake.recordInvocation("GetSpaceFromCf", []interface{}{spaceGuid})
+	}{ctx, spaceGuid})
+	fake.recordInvocation("GetSpaceFromCf", []interface{}{ctx, spaceGuid})
 	fake.getSpaceFromCfMutex.Unlock()
 	if fake.GetSpaceFromCfStub != nil {
-		return fake.GetSpaceFromCfStub(spaceGuid)
+		return fake.GetSpaceFromCfStub(ctx, spaceGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -461,10 +478,10 @@ func (fake *FakeFinderRepository) GetSpaceFromCfCallCount() int {
 	return len(fake.getSpaceFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetSpaceFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetSpaceFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getSpaceFromCfMutex.RLock()
 	defer fake.getSpaceFromCfMutex.RUnlock()
-	return fake.getSpaceFromCfArgsForCall[i].spaceGuid
+	return fake.getSpaceFromCfArgsForCall[i].ctx, fake.getSpaceFromCfArgsForCall[i].spaceGuid
 }
 
 func (fake *FakeFinderRepository) GetSpaceFromCfReturns(result1 models.Space, result2 error) {
@@ -489,16 +506,17 @@ func (fake *FakeFinderRepository) GetSpaceFromCfReturnsOnCall(i int, result1 mod
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetAppFromCf(appGuid string) (models.Application, error) {
+func (fake *FakeFinderRepository) GetAppFromCf(ctx context.Context, appGuid string) (models.Application, error) {
 	fake.getAppFromCfMutex.Lock()
 	ret, specificReturn := fake.getAppFromCfReturnsOnCall[len(fake.getAppFromCfArgsForCall)]
 	fake.getAppFromCfArgsForCall = append(fake.getAppFromCfArgsForCall, struct {
+		ctx     context.Context
 		appGuid string
-	}{appGuid})
-	fake.recordInvocation("GetAppFromCf", []interface{}{appGuid})
+	}{ctx, appGuid})
+	fake.recordInvocation("GetAppFromCf", []interface{}{ctx, appGuid})
 	fake.getAppFromCfMutex.Unlock()
 	if fake.GetAppFromCfStub != nil {
-		return fake.GetAppFromCfStub(appGuid)
+		return fake.GetAppFromCfStub(ctx, appGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -512,10 +530,10 @@ func (fake *FakeFinderRepository) GetAppFromCfCallCount() int {
 	return len(fake.getAppFromCfArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetAppFromCfArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetAppFromCfArgsForCall(i int) (context.Context, string) {
 	fake.getAppFromCfMutex.RLock()
 	defer fake.getAppFromCfMutex.RUnlock()
-	return fake.getAppFromCfArgsForCall[i].appGuid
+	return fake.getAppFromCfArgsForCall[i].ctx, fake.getAppFromCfArgsForCall[i].appGuid
 }
 
 func (fake *FakeFinderRepository) GetAppFromCfReturns(result1 models.Application, result2 error) {
@@ -540,16 +558,17 @@ func (fake *FakeFinderRepository) GetAppFromCfReturnsOnCall(i int, result1 model
 	}{result1, result2}
 }
 
-func (fake *FakeFinderRepository) GetServiceBindingsFromApp(appGuid string) ([]cf_client.ServiceBindingFields, error) {
+func (fake *FakeFinderRepository) GetServiceBindingsFromApp(ctx context.Context, appGuid string) ([]cf_client.ServiceBindingFields, error) {
 	fake.getServiceBindingsFromAppMutex.Lock()
 	ret, specificReturn := fake.getServiceBindingsFromAppReturnsOnCall[len(fake.getServiceBindingsFromAppArgsForCall)]
 	fake.getServiceBindingsFromAppArgsForCall = append(fake.getServiceBindingsFromAppArgsForCall, struct {
+		ctx     context.Context
 		appGuid string
-	}{appGuid})
-	fake.recordInvocation("GetServiceBindingsFromApp", []interface{}{appGuid})
+	}{ctx, appGuid})
+	fake.recordInvocation("GetServiceBindingsFromApp", []interface{}{ctx, appGuid})
 	fake.getServiceBindingsFromAppMutex.Unlock()
 	if fake.GetServiceBindingsFromAppStub != nil {
-		return fake.GetServiceBindingsFromAppStub(appGuid)
+		return fake.GetServiceBindingsFromAppStub(ctx, appGuid)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -563,10 +582,10 @@ func (fake *FakeFinderRepository) GetServiceBindingsFromAppCallCount() int {
 	return len(fake.getServiceBindingsFromAppArgsForCall)
 }
 
-func (fake *FakeFinderRepository) GetServiceBindingsFromAppArgsForCall(i int) string {
+func (fake *FakeFinderRepository) GetServiceBindingsFromAppArgsForCall(i int) (context.Context, string) {
 	fake.getServiceBindingsFromAppMutex.RLock()
 	defer fake.getServiceBindingsFromAppMutex.RUnlock()
-	return fake.getServiceBindingsFromAppArgsForCall[i].appGuid
+	return fake.getServiceBindingsFromAppArgsForCall[i].ctx, fake.getServiceBindingsFromAppArgsForCall[i].appGuid
 }
 
 func (fake *FakeFinderRepository) GetServiceBindingsFromAppReturns(result1 []cf_client.ServiceBindingFields, result2 error) {
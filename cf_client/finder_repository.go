@@ -5,19 +5,21 @@ import (
 	"code.cloudfoundry.org/cli/cf/errors"
 	"code.cloudfoundry.org/cli/cf/models"
 	"code.cloudfoundry.org/cli/cf/net"
+	"context"
 	"fmt"
 )
 
+// FinderRepository looks up resources with their relations, lookups stop when ctx is canceled.
 type FinderRepository interface {
-	GetDomainFromCf(domain models.DomainFields) (models.DomainFields, error)
-	GetBuildpackFromCf(bpGuid string) (models.Buildpack, error)
-	GetQuotaFromCf(quotaGuid string, isOrgQuota bool) (interface{}, error)
-	GetRouteFromCf(routeGuid string) (models.Route, error)
-	GetSecGroupFromCf(secGroupId string) (models.SecurityGroup, error)
-	GetServiceFromCf(svcGuid string) (models.ServiceInstance, error)
-	GetSpaceFromCf(spaceGuid string) (models.Space, error)
-	GetAppFromCf(appGuid string) (models.Application, error)
-	GetServiceBindingsFromApp(appGuid string) ([]ServiceBindingFields, error)
+	GetDomainFromCf(ctx context.Context, domain models.DomainFields) (models.DomainFields, error)
+	GetBuildpackFromCf(ctx context.Context, bpGuid string) (models.Buildpack, error)
+	GetQuotaFromCf(ctx context.Context, quotaGuid string, isOrgQuota bool) (interface{}, error)
+	GetRouteFromCf(ctx context.Context, routeGuid string) (models.Route, error)
+	GetSecGroupFromCf(ctx context.Context, secGroupId string) (models.SecurityGroup, error)
+	GetServiceFromCf(ctx context.Context, svcGuid string) (models.ServiceInstance, error)
+	GetSpaceFromCf(ctx context.Context, spaceGuid string) (models.Space, error)
+	GetAppFromCf(ctx context.Context, appGuid string) (models.Application, error)
+	GetServiceBindingsFromApp(ctx context.Context, appGuid string) ([]ServiceBindingFields, error)
 }
 
 type Finder struct {
@@ -31,16 +33,41 @@ func NewFinderRepository(config Config, ccGateway net.Gateway) FinderRepository
 		ccGateway: ccGateway,
 	}
 }
-func (f Finder) GetDomainFromCf(domain models.DomainFields) (models.DomainFields, error) {
+
+// getResource gets a resource through cloud controller gateway if ctx is not canceled,
+// in-flight requests are aborted by the http client when provider context is canceled.
+func (f Finder) getResource(ctx context.Context, url string, resource interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.ccGateway.GetResource(url, resource)
+}
+
+// listPaginatedResources lists resources of every pages and stops when ctx is canceled
+func (f Finder) listPaginatedResources(ctx context.Context, path string, resource interface{}, cb func(interface{}) bool) error {
+	err := f.ccGateway.ListPaginatedResources(f.config.ApiEndpoint, path, resource, func(resource interface{}) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return cb(resource)
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+func (f Finder) GetDomainFromCf(ctx context.Context, domain models.DomainFields) (models.DomainFields, error) {
 	res := resources.DomainResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/private_domains/%s?inline-relations-depth=1",
 			f.config.ApiEndpoint,
 			domain.GUID,
 		),
 		&res)
 	if err != nil {
-		err = f.ccGateway.GetResource(
+		err = f.getResource(
+			ctx,
 			fmt.Sprintf("%s/v2/shared_domains/%s?inline-relations-depth=1",
 				f.config.ApiEndpoint,
 				domain.GUID,
@@ -55,9 +82,10 @@ func (f Finder) GetDomainFromCf(domain models.DomainFields) (models.DomainFields
 	}
 	return res.ToFields(), nil
 }
-func (f Finder) GetBuildpackFromCf(bpGuid string) (models.Buildpack, error) {
+func (f Finder) GetBuildpackFromCf(ctx context.Context, bpGuid string) (models.Buildpack, error) {
 	res := resources.BuildpackResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/buildpacks/%s?inline-relations-depth=1", f.config.ApiEndpoint, bpGuid),
 		&res)
 	if err != nil {
@@ -68,12 +96,13 @@ func (f Finder) GetBuildpackFromCf(bpGuid string) (models.Buildpack, error) {
 	}
 	return res.ToFields(), nil
 }
-func (f Finder) GetQuotaFromCf(quotaGuid string, isOrgQuota bool) (interface{}, error) {
+func (f Finder) GetQuotaFromCf(ctx context.Context, quotaGuid string, isOrgQuota bool) (interface{}, error) {
 	var err error
 
 	if isOrgQuota {
 		res := resources.QuotaResource{}
-		err = f.ccGateway.GetResource(
+		err = f.getResource(
+			ctx,
 			fmt.Sprintf("%s/v2/quota_definitions/%s?inline-relations-depth=1", f.config.ApiEndpoint, quotaGuid),
 			&res)
 		if err != nil {
@@ -85,7 +114,8 @@ func (f Finder) GetQuotaFromCf(quotaGuid string, isOrgQuota bool) (interface{},
 		return res.ToFields(), nil
 	}
 	res := resources.SpaceQuotaResource{}
-	err = f.ccGateway.GetResource(
+	err = f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/space_quota_definitions/%s?inline-relations-depth=1", f.config.ApiEndpoint, quotaGuid),
 		&res)
 	if err != nil {
@@ -96,9 +126,10 @@ func (f Finder) GetQuotaFromCf(quotaGuid string, isOrgQuota bool) (interface{},
 	}
 	return res.ToModel(), nil
 }
-func (f Finder) GetRouteFromCf(routeGuid string) (models.Route, error) {
+func (f Finder) GetRouteFromCf(ctx context.Context, routeGuid string) (models.Route, error) {
 	routeRes := resources.RouteResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/routes/%s?inline-relations-depth=1", f.config.ApiEndpoint, routeGuid),
 		&routeRes)
 	if err != nil {
@@ -109,9 +140,10 @@ func (f Finder) GetRouteFromCf(routeGuid string) (models.Route, error) {
 	}
 	return routeRes.ToModel(), nil
 }
-func (f Finder) GetSecGroupFromCf(secGroupId string) (models.SecurityGroup, error) {
+func (f Finder) GetSecGroupFromCf(ctx context.Context, secGroupId string) (models.SecurityGroup, error) {
 	res := resources.SecurityGroupResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/security_groups/%s?inline-relations-depth=1", f.config.ApiEndpoint, secGroupId),
 		&res)
 	if err != nil {
@@ -121,8 +153,8 @@ func (f Finder) GetSecGroupFromCf(secGroupId string) (models.SecurityGroup, erro
 		return models.SecurityGroup{}, err
 	}
 	secGroup := res.ToModel()
-	err = f.ccGateway.ListPaginatedResources(
-		f.config.ApiEndpoint,
+	err = f.listPaginatedResources(
+		ctx,
 		secGroup.SpaceURL+"?inline-relations-depth=1",
 		resources.SpaceResource{},
 		func(resource interface{}) bool {
@@ -135,9 +167,10 @@ func (f Finder) GetSecGroupFromCf(secGroupId string) (models.SecurityGroup, erro
 	)
 	return secGroup, nil
 }
-func (f Finder) GetServiceFromCf(svcGuid string) (models.ServiceInstance, error) {
+func (f Finder) GetServiceFromCf(ctx context.Context, svcGuid string) (models.ServiceInstance, error) {
 	res := ServiceInstanceResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/service_instances/%s?inline-relations-depth=1", f.config.ApiEndpoint, svcGuid),
 		&res)
 	if err != nil {
@@ -150,9 +183,10 @@ func (f Finder) GetServiceFromCf(svcGuid string) (models.ServiceInstance, error)
 
 	return model, nil
 }
-func (f Finder) GetSpaceFromCf(spaceGuid string) (models.Space, error) {
+func (f Finder) GetSpaceFromCf(ctx context.Context, spaceGuid string) (models.Space, error) {
 	res := resources.SpaceResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/spaces/%s?inline-relations-depth=1", f.config.ApiEndpoint, spaceGuid),
 		&res)
 	if _, ok := err.(*errors.HTTPNotFoundError); ok {
@@ -163,10 +197,10 @@ func (f Finder) GetSpaceFromCf(spaceGuid string) (models.Space, error) {
 	}
 	return res.ToModel(), nil
 }
-func (f Finder) GetServiceBindingsFromApp(appGuid string) ([]ServiceBindingFields, error) {
+func (f Finder) GetServiceBindingsFromApp(ctx context.Context, appGuid string) ([]ServiceBindingFields, error) {
 	serviceBindings := []ServiceBindingFields{}
-	err := f.ccGateway.ListPaginatedResources(
-		f.config.ApiEndpoint,
+	err := f.listPaginatedResources(
+		ctx,
 		fmt.Sprintf("/v2/apps/%s/service_bindings", appGuid),
 		ServiceBindingResource{},
 		func(resource interface{}) bool {
@@ -179,9 +213,10 @@ func (f Finder) GetServiceBindingsFromApp(appGuid string) ([]ServiceBindingField
 	return serviceBindings, err
 
 }
-func (f Finder) GetAppFromCf(appGuid string) (models.Application, error) {
+func (f Finder) GetAppFromCf(ctx context.Context, appGuid string) (models.Application, error) {
 	res := resources.ApplicationResource{}
-	err := f.ccGateway.GetResource(
+	err := f.getResource(
+		ctx,
 		fmt.Sprintf("%s/v2/apps/%s?inline-relations-depth=1", f.config.ApiEndpoint, appGuid),
 		&res)
 	if _, ok := err.(*errors.HTTPNotFoundError); ok {
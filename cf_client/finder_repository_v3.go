@@ -0,0 +1,682 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/cf/errors"
+	"code.cloudfoundry.org/cli/cf/models"
+	"code.cloudfoundry.org/cli/cf/net"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FinderV3 is a FinderRepository using cloud controller v3 api with include and fields queries
+// instead of heavy v2 inline-relations-depth calls.
+// It gives same models than v2 Finder, only fields used by resources are filled
+// (e.g.: applications, domains and service instances of a space are not retrieved).
+// It falls back to v2 Finder for what v3 can't give without losing data (docker applications).
+type FinderV3 struct {
+	Finder
+}
+
+func NewFinderRepositoryV3(config Config, ccGateway net.Gateway) FinderRepository {
+	return &FinderV3{
+		Finder: Finder{
+			config:    config,
+			ccGateway: ccGateway,
+		},
+	}
+}
+
+type v3Relationship struct {
+	Data *struct {
+		GUID string `json:"guid"`
+	} `json:"data"`
+}
+
+func (r v3Relationship) GUID() string {
+	if r.Data == nil {
+		return ""
+	}
+	return r.Data.GUID
+}
+
+type v3Relationships struct {
+	Data []struct {
+		GUID string `json:"guid"`
+	} `json:"data"`
+}
+
+type v3Named struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type v3Page struct {
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+	Resources []json.RawMessage `json:"resources"`
+}
+
+type v3Domain struct {
+	GUID        string `json:"guid"`
+	Name        string `json:"name"`
+	RouterGroup *struct {
+		GUID string `json:"guid"`
+	} `json:"router_group"`
+	Relationships struct {
+		Organization v3Relationship `json:"organization"`
+	} `json:"relationships"`
+}
+
+func (d v3Domain) ToFields() models.DomainFields {
+	domain := models.DomainFields{
+		GUID:                   d.GUID,
+		Name:                   d.Name,
+		OwningOrganizationGUID: d.Relationships.Organization.GUID(),
+		Shared:                 d.Relationships.Organization.GUID() == "",
+	}
+	if d.RouterGroup != nil {
+		domain.RouterGroupGUID = d.RouterGroup.GUID
+	}
+	return domain
+}
+
+type v3Buildpack struct {
+	GUID     string `json:"guid"`
+	Name     string `json:"name"`
+	Stack    string `json:"stack"`
+	Position *int   `json:"position"`
+	Enabled  *bool  `json:"enabled"`
+	Locked   *bool  `json:"locked"`
+	Filename string `json:"filename"`
+}
+
+type v3Quota struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+	Apps struct {
+		TotalMemoryInMb      *int64 `json:"total_memory_in_mb"`
+		PerProcessMemoryInMb *int64 `json:"per_process_memory_in_mb"`
+		TotalInstances       *int   `json:"total_instances"`
+	} `json:"apps"`
+	Services struct {
+		PaidServicesAllowed   bool `json:"paid_services_allowed"`
+		TotalServiceInstances *int `json:"total_service_instances"`
+	} `json:"services"`
+	Routes struct {
+		TotalRoutes        *int `json:"total_routes"`
+		TotalReservedPorts *int `json:"total_reserved_ports"`
+	} `json:"routes"`
+	Relationships struct {
+		Organization v3Relationship `json:"organization"`
+	} `json:"relationships"`
+}
+
+func (q v3Quota) ToFields() models.QuotaFields {
+	return models.QuotaFields{
+		GUID:                    q.GUID,
+		Name:                    q.Name,
+		MemoryLimit:             unlimitedInt64(q.Apps.TotalMemoryInMb),
+		InstanceMemoryLimit:     unlimitedInt64(q.Apps.PerProcessMemoryInMb),
+		RoutesLimit:             unlimitedInt(q.Routes.TotalRoutes),
+		ServicesLimit:           unlimitedInt(q.Services.TotalServiceInstances),
+		NonBasicServicesAllowed: q.Services.PaidServicesAllowed,
+		AppInstanceLimit:        unlimitedInt(q.Apps.TotalInstances),
+		ReservedRoutePorts:      json.Number(fmt.Sprint(unlimitedInt(q.Routes.TotalReservedPorts))),
+	}
+}
+func (q v3Quota) ToSpaceQuota() models.SpaceQuota {
+	fields := q.ToFields()
+	return models.SpaceQuota{
+		GUID:                    fields.GUID,
+		Name:                    fields.Name,
+		MemoryLimit:             fields.MemoryLimit,
+		InstanceMemoryLimit:     fields.InstanceMemoryLimit,
+		RoutesLimit:             fields.RoutesLimit,
+		ServicesLimit:           fields.ServicesLimit,
+		NonBasicServicesAllowed: fields.NonBasicServicesAllowed,
+		OrgGUID:                 q.Relationships.Organization.GUID(),
+		AppInstanceLimit:        fields.AppInstanceLimit,
+		ReservedRoutePortsLimit: fields.ReservedRoutePorts,
+	}
+}
+
+// unlimitedInt and unlimitedInt64 convert v3 null limits to v2 -1 unlimited value
+func unlimitedInt(value *int) int {
+	if value == nil {
+		return -1
+	}
+	return *value
+}
+func unlimitedInt64(value *int64) int64 {
+	if value == nil {
+		return -1
+	}
+	return *value
+}
+
+type v3Route struct {
+	GUID         string `json:"guid"`
+	Host         string `json:"host"`
+	Path         string `json:"path"`
+	Port         *int   `json:"port"`
+	Destinations []struct {
+		App struct {
+			GUID string `json:"guid"`
+		} `json:"app"`
+	} `json:"destinations"`
+	Relationships struct {
+		Space  v3Relationship `json:"space"`
+		Domain v3Relationship `json:"domain"`
+	} `json:"relationships"`
+	Included struct {
+		Domains []v3Domain `json:"domains"`
+		Spaces  []v3Named  `json:"spaces"`
+	} `json:"included"`
+}
+
+func (r v3Route) ToSummary(domains []v3Domain) models.RouteSummary {
+	summary := models.RouteSummary{
+		GUID: r.GUID,
+		Host: r.Host,
+		Path: r.Path,
+		Domain: models.DomainFields{
+			GUID: r.Relationships.Domain.GUID(),
+		},
+	}
+	if r.Port != nil {
+		summary.Port = *r.Port
+	}
+	for _, domain := range domains {
+		if domain.GUID == summary.Domain.GUID {
+			summary.Domain = domain.ToFields()
+		}
+	}
+	return summary
+}
+
+type v3SecurityGroup struct {
+	GUID          string                   `json:"guid"`
+	Name          string                   `json:"name"`
+	Rules         []map[string]interface{} `json:"rules"`
+	Relationships struct {
+		RunningSpaces v3Relationships `json:"running_spaces"`
+	} `json:"relationships"`
+}
+
+func (s v3SecurityGroup) ToFields() models.SecurityGroupFields {
+	rules := make([]map[string]interface{}, len(s.Rules))
+	for i, rule := range s.Rules {
+		// v3 gives null for rule keys not set where v2 omits them
+		rules[i] = make(map[string]interface{})
+		for key, value := range rule {
+			if value != nil {
+				rules[i][key] = value
+			}
+		}
+	}
+	return models.SecurityGroupFields{
+		GUID:  s.GUID,
+		Name:  s.Name,
+		Rules: rules,
+	}
+}
+
+type v3ServiceInstance struct {
+	GUID            string        `json:"guid"`
+	Name            string        `json:"name"`
+	Tags            []string      `json:"tags"`
+	DashboardURL    string        `json:"dashboard_url"`
+	SysLogDrainURL  string        `json:"syslog_drain_url"`
+	RouteServiceURL string        `json:"route_service_url"`
+	LastOperation   LastOperation `json:"last_operation"`
+	Relationships   struct {
+		ServicePlan v3Relationship `json:"service_plan"`
+	} `json:"relationships"`
+	Included struct {
+		ServicePlans []struct {
+			v3Named
+			Relationships struct {
+				ServiceOffering v3Relationship `json:"service_offering"`
+			} `json:"relationships"`
+		} `json:"service_plans"`
+		ServiceOfferings []v3Named `json:"service_offerings"`
+	} `json:"included"`
+}
+
+func (s v3ServiceInstance) ToModel() models.ServiceInstance {
+	instance := models.ServiceInstance{
+		ServiceInstanceFields: models.ServiceInstanceFields{
+			GUID:            s.GUID,
+			Name:            s.Name,
+			Tags:            s.Tags,
+			DashboardURL:    s.DashboardURL,
+			SysLogDrainURL:  s.SysLogDrainURL,
+			RouteServiceURL: s.RouteServiceURL,
+			LastOperation: models.LastOperationFields{
+				Type:        s.LastOperation.Type,
+				State:       s.LastOperation.State,
+				Description: s.LastOperation.Description,
+				CreatedAt:   s.LastOperation.CreatedAt,
+				UpdatedAt:   s.LastOperation.UpdatedAt,
+			},
+		},
+		ServiceBindings: []models.ServiceBindingFields{},
+		ServiceKeys:     []models.ServiceKeyFields{},
+	}
+	for _, plan := range s.Included.ServicePlans {
+		if plan.GUID != s.Relationships.ServicePlan.GUID() {
+			continue
+		}
+		instance.ServicePlan = models.ServicePlanFields{
+			GUID:                plan.GUID,
+			Name:                plan.Name,
+			ServiceOfferingGUID: plan.Relationships.ServiceOffering.GUID(),
+		}
+	}
+	for _, offering := range s.Included.ServiceOfferings {
+		if offering.GUID != instance.ServicePlan.ServiceOfferingGUID {
+			continue
+		}
+		instance.ServiceOffering = models.ServiceOfferingFields{
+			GUID:  offering.GUID,
+			Label: offering.Name,
+		}
+	}
+	return instance
+}
+
+type v3Space struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	Relationships struct {
+		Organization v3Relationship `json:"organization"`
+		Quota        v3Relationship `json:"quota"`
+	} `json:"relationships"`
+	Included struct {
+		Organizations []v3Named `json:"organizations"`
+	} `json:"included"`
+}
+
+type v3App struct {
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Lifecycle struct {
+		Type string `json:"type"`
+		Data struct {
+			Buildpacks []string `json:"buildpacks"`
+			Stack      string   `json:"stack"`
+		} `json:"data"`
+	} `json:"lifecycle"`
+	Relationships struct {
+		Space v3Relationship `json:"space"`
+	} `json:"relationships"`
+}
+
+type v3Process struct {
+	GUID        string  `json:"guid"`
+	Type        string  `json:"type"`
+	Command     *string `json:"command"`
+	Instances   int     `json:"instances"`
+	MemoryInMb  int64   `json:"memory_in_mb"`
+	DiskInMb    int64   `json:"disk_in_mb"`
+	HealthCheck struct {
+		Type string `json:"type"`
+		Data struct {
+			Timeout  *int    `json:"timeout"`
+			Endpoint *string `json:"endpoint"`
+		} `json:"data"`
+	} `json:"health_check"`
+}
+
+type v3ServiceCredentialBinding struct {
+	GUID          string `json:"guid"`
+	Relationships struct {
+		App             v3Relationship `json:"app"`
+		ServiceInstance v3Relationship `json:"service_instance"`
+	} `json:"relationships"`
+	Links struct {
+		Self struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// getV3Resource gets a v3 resource and gives found at false when it doesn't exist
+func (f FinderV3) getV3Resource(ctx context.Context, path string, query url.Values, resource interface{}) (found bool, err error) {
+	err = f.getResource(ctx, f.v3URL(path, query), resource)
+	if _, ok := err.(*errors.HTTPNotFoundError); ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// listV3Resources calls cb on each resource of every pages
+func (f FinderV3) listV3Resources(ctx context.Context, path string, query url.Values, cb func(resource json.RawMessage) error) error {
+	nextURL := f.v3URL(path, query)
+	for nextURL != "" {
+		page := v3Page{}
+		err := f.getResource(ctx, nextURL, &page)
+		if err != nil {
+			return err
+		}
+		for _, resource := range page.Resources {
+			err = cb(resource)
+			if err != nil {
+				return err
+			}
+		}
+		nextURL = ""
+		if page.Pagination.Next != nil {
+			nextURL = page.Pagination.Next.Href
+		}
+	}
+	return nil
+}
+func (f FinderV3) v3URL(path string, query url.Values) string {
+	if len(query) == 0 {
+		return f.config.ApiEndpoint + path
+	}
+	return fmt.Sprintf("%s%s?%s", f.config.ApiEndpoint, path, query.Encode())
+}
+func (f FinderV3) GetDomainFromCf(ctx context.Context, domain models.DomainFields) (models.DomainFields, error) {
+	res := v3Domain{}
+	found, err := f.getV3Resource(ctx, "/v3/domains/"+domain.GUID, nil, &res)
+	if err != nil || !found {
+		return models.DomainFields{}, err
+	}
+	return res.ToFields(), nil
+}
+func (f FinderV3) GetBuildpackFromCf(ctx context.Context, bpGuid string) (models.Buildpack, error) {
+	res := v3Buildpack{}
+	found, err := f.getV3Resource(ctx, "/v3/buildpacks/"+bpGuid, nil, &res)
+	if err != nil || !found {
+		return models.Buildpack{}, err
+	}
+	return models.Buildpack{
+		GUID:     res.GUID,
+		Name:     res.Name,
+		Stack:    res.Stack,
+		Position: res.Position,
+		Enabled:  res.Enabled,
+		Locked:   res.Locked,
+		Filename: res.Filename,
+	}, nil
+}
+func (f FinderV3) GetQuotaFromCf(ctx context.Context, quotaGuid string, isOrgQuota bool) (interface{}, error) {
+	res := v3Quota{}
+	path := "/v3/space_quotas/" + quotaGuid
+	if isOrgQuota {
+		path = "/v3/organization_quotas/" + quotaGuid
+	}
+	found, err := f.getV3Resource(ctx, path, nil, &res)
+	if err != nil || !found {
+		return models.QuotaFields{}, err
+	}
+	if isOrgQuota {
+		return res.ToFields(), nil
+	}
+	return res.ToSpaceQuota(), nil
+}
+func (f FinderV3) GetRouteFromCf(ctx context.Context, routeGuid string) (models.Route, error) {
+	res := v3Route{}
+	found, err := f.getV3Resource(ctx, "/v3/routes/"+routeGuid, url.Values{"include": {"domain,space"}}, &res)
+	if err != nil || !found {
+		return models.Route{}, err
+	}
+	summary := res.ToSummary(res.Included.Domains)
+	route := models.Route{
+		GUID:   summary.GUID,
+		Host:   summary.Host,
+		Domain: summary.Domain,
+		Path:   summary.Path,
+		Port:   summary.Port,
+		Space: models.SpaceFields{
+			GUID: res.Relationships.Space.GUID(),
+		},
+	}
+	for _, space := range res.Included.Spaces {
+		if space.GUID == route.Space.GUID {
+			route.Space.Name = space.Name
+		}
+	}
+	for _, destination := range res.Destinations {
+		route.Apps = append(route.Apps, models.ApplicationFields{GUID: destination.App.GUID})
+	}
+	query := url.Values{
+		"route_guids": {routeGuid},
+		"include":     {"service_instance"},
+	}
+	page := struct {
+		Included struct {
+			ServiceInstances []v3Named `json:"service_instances"`
+		} `json:"included"`
+	}{}
+	err = f.getResource(ctx, f.v3URL("/v3/service_route_bindings", query), &page)
+	if err != nil {
+		return models.Route{}, err
+	}
+	for _, svc := range page.Included.ServiceInstances {
+		route.ServiceInstance = models.ServiceInstanceFields{
+			GUID: svc.GUID,
+			Name: svc.Name,
+		}
+	}
+	return route, nil
+}
+func (f FinderV3) GetSecGroupFromCf(ctx context.Context, secGroupId string) (models.SecurityGroup, error) {
+	res := v3SecurityGroup{}
+	found, err := f.getV3Resource(ctx, "/v3/security_groups/"+secGroupId, nil, &res)
+	if err != nil || !found {
+		return models.SecurityGroup{}, err
+	}
+	secGroup := models.SecurityGroup{
+		SecurityGroupFields: res.ToFields(),
+	}
+	for _, space := range res.Relationships.RunningSpaces.Data {
+		secGroup.Spaces = append(secGroup.Spaces, models.Space{
+			SpaceFields: models.SpaceFields{GUID: space.GUID},
+		})
+	}
+	return secGroup, nil
+}
+func (f FinderV3) GetServiceFromCf(ctx context.Context, svcGuid string) (models.ServiceInstance, error) {
+	res := v3ServiceInstance{}
+	query := url.Values{
+		"fields[service_plan]":                  {"guid,name,relationships.service_offering"},
+		"fields[service_plan.service_offering]": {"guid,name"},
+	}
+	found, err := f.getV3Resource(ctx, "/v3/service_instances/"+svcGuid, query, &res)
+	if err != nil || !found {
+		return models.ServiceInstance{}, err
+	}
+	return res.ToModel(), nil
+}
+func (f FinderV3) GetSpaceFromCf(ctx context.Context, spaceGuid string) (models.Space, error) {
+	res := v3Space{}
+	found, err := f.getV3Resource(ctx, "/v3/spaces/"+spaceGuid, url.Values{"include": {"organization"}}, &res)
+	if err != nil || !found {
+		return models.Space{}, err
+	}
+	space := models.Space{
+		SpaceFields: models.SpaceFields{
+			GUID: res.GUID,
+			Name: res.Name,
+		},
+		Organization: models.OrganizationFields{
+			GUID: res.Relationships.Organization.GUID(),
+		},
+		SpaceQuotaGUID: res.Relationships.Quota.GUID(),
+	}
+	for _, org := range res.Included.Organizations {
+		if org.GUID == space.Organization.GUID {
+			space.Organization.Name = org.Name
+		}
+	}
+	ssh := struct {
+		Enabled bool `json:"enabled"`
+	}{}
+	err = f.getResource(ctx, f.v3URL(fmt.Sprintf("/v3/spaces/%s/features/ssh", spaceGuid), nil), &ssh)
+	if err != nil {
+		return models.Space{}, err
+	}
+	space.AllowSSH = ssh.Enabled
+	err = f.listV3Resources(ctx, "/v3/security_groups", url.Values{"running_space_guids": {spaceGuid}}, func(resource json.RawMessage) error {
+		secGroup := v3SecurityGroup{}
+		err := json.Unmarshal(resource, &secGroup)
+		if err != nil {
+			return err
+		}
+		space.SecurityGroups = append(space.SecurityGroups, secGroup.ToFields())
+		return nil
+	})
+	if err != nil {
+		return models.Space{}, err
+	}
+	return space, nil
+}
+func (f FinderV3) GetServiceBindingsFromApp(ctx context.Context, appGuid string) ([]ServiceBindingFields, error) {
+	serviceBindings := []ServiceBindingFields{}
+	query := url.Values{
+		"app_guids": {appGuid},
+		"type":      {"app"},
+	}
+	err := f.listV3Resources(ctx, "/v3/service_credential_bindings", query, func(resource json.RawMessage) error {
+		binding := v3ServiceCredentialBinding{}
+		err := json.Unmarshal(resource, &binding)
+		if err != nil {
+			return err
+		}
+		serviceBindings = append(serviceBindings, ServiceBindingFields{
+			GUID:                binding.GUID,
+			URL:                 binding.Links.Self.Href,
+			AppGUID:             binding.Relationships.App.GUID(),
+			ServiceInstanceGUID: binding.Relationships.ServiceInstance.GUID(),
+		})
+		return nil
+	})
+	return serviceBindings, err
+}
+func (f FinderV3) GetAppFromCf(ctx context.Context, appGuid string) (models.Application, error) {
+	res := v3App{}
+	found, err := f.getV3Resource(ctx, "/v3/apps/"+appGuid, nil, &res)
+	if err != nil || !found {
+		return models.Application{}, err
+	}
+	// docker image and stack of a docker app are only given by v2
+	if res.Lifecycle.Type == "docker" {
+		return f.Finder.GetAppFromCf(ctx, appGuid)
+	}
+	app := models.Application{
+		ApplicationFields: models.ApplicationFields{
+			GUID:      res.GUID,
+			Name:      res.Name,
+			State:     strings.ToLower(res.State),
+			SpaceGUID: res.Relationships.Space.GUID(),
+		},
+	}
+	// backend is not given by v3, a v2 app without relations is light to get
+	v2App := struct {
+		Entity struct {
+			Diego bool `json:"diego"`
+		} `json:"entity"`
+	}{}
+	err = f.getResource(ctx, fmt.Sprintf("%s/v2/apps/%s", f.config.ApiEndpoint, appGuid), &v2App)
+	if err != nil {
+		return models.Application{}, err
+	}
+	app.Diego = v2App.Entity.Diego
+	if len(res.Lifecycle.Data.Buildpacks) > 0 {
+		app.Buildpack = res.Lifecycle.Data.Buildpacks[0]
+	}
+
+	// an app without web process (e.g.: only workers declared) has no instances and no command
+	process := v3Process{}
+	_, err = f.getV3Resource(ctx, fmt.Sprintf("/v3/apps/%s/processes/web", appGuid), nil, &process)
+	if err != nil {
+		return models.Application{}, err
+	}
+	if process.Command != nil {
+		app.Command = *process.Command
+	}
+	app.InstanceCount = process.Instances
+	app.Memory = process.MemoryInMb
+	app.DiskQuota = process.DiskInMb
+	app.HealthCheckType = process.HealthCheck.Type
+	if process.HealthCheck.Data.Timeout != nil {
+		app.HealthCheckTimeout = *process.HealthCheck.Data.Timeout
+	}
+	if process.HealthCheck.Data.Endpoint != nil {
+		app.HealthCheckHTTPEndpoint = *process.HealthCheck.Data.Endpoint
+	}
+
+	envVars := struct {
+		Var map[string]interface{} `json:"var"`
+	}{}
+	err = f.getResource(ctx, f.v3URL(fmt.Sprintf("/v3/apps/%s/environment_variables", appGuid), nil), &envVars)
+	if err != nil {
+		return models.Application{}, err
+	}
+	app.EnvironmentVars = envVars.Var
+
+	// ssh_enabled endpoint gives false when space or platform disallows ssh, feature is what user set
+	ssh := struct {
+		Enabled bool `json:"enabled"`
+	}{}
+	err = f.getResource(ctx, f.v3URL(fmt.Sprintf("/v3/apps/%s/features/ssh", appGuid), nil), &ssh)
+	if err != nil {
+		return models.Application{}, err
+	}
+	app.EnableSSH = ssh.Enabled
+
+	app.Stack = &models.Stack{Name: res.Lifecycle.Data.Stack}
+	err = f.listV3Resources(ctx, "/v3/stacks", url.Values{"names": {res.Lifecycle.Data.Stack}}, func(resource json.RawMessage) error {
+		stack := v3Named{}
+		err := json.Unmarshal(resource, &stack)
+		if err != nil {
+			return err
+		}
+		app.Stack.GUID = stack.GUID
+		app.StackGUID = stack.GUID
+		return nil
+	})
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	nextURL := f.v3URL("/v3/routes", url.Values{"app_guids": {appGuid}, "include": {"domain"}})
+	for nextURL != "" {
+		routesPage := struct {
+			v3Page
+			Included struct {
+				Domains []v3Domain `json:"domains"`
+			} `json:"included"`
+		}{}
+		err = f.getResource(ctx, nextURL, &routesPage)
+		if err != nil {
+			return models.Application{}, err
+		}
+		for _, resource := range routesPage.Resources {
+			route := v3Route{}
+			err = json.Unmarshal(resource, &route)
+			if err != nil {
+				return models.Application{}, err
+			}
+			app.Routes = append(app.Routes, route.ToSummary(routesPage.Included.Domains))
+		}
+		nextURL = ""
+		if routesPage.Pagination.Next != nil {
+			nextURL = routesPage.Pagination.Next.Href
+		}
+	}
+	return app, nil
+}
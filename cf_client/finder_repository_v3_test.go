@@ -0,0 +1,196 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"code.cloudfoundry.org/cli/cf/models"
+	"context"
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("FinderV3", func() {
+	var server *httptest.Server
+	var responses map[string]string
+	var finder FinderRepository
+	BeforeEach(func() {
+		responses = map[string]string{
+			"/v3/apps/app-guid":                       `{"guid": "app-guid", "name": "my-app", "state": "STARTED", "lifecycle": {"type": "buildpack", "data": {"stack": "cflinuxfs3"}}}`,
+			"/v2/apps/app-guid":                       `{"metadata": {"guid": "app-guid"}, "entity": {"diego": false}}`,
+			"/v3/apps/app-guid/environment_variables": `{"var": {}}`,
+			"/v3/apps/app-guid/features/ssh":          `{"name": "ssh", "enabled": true}`,
+			"/v3/apps/app-guid/ssh_enabled":           `{"enabled": false, "reason": "Disabled globally"}`,
+			"/v3/stacks":                              `{"resources": []}`,
+			"/v3/routes":                              `{"resources": []}`,
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			response, ok := responses[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Process not found"}]}`))
+				return
+			}
+			w.Write([]byte(response))
+		}))
+		repository, gateway := newTestGateway(server.URL)
+		finder = NewFinderRepositoryV3(Config{ApiEndpoint: repository.APIEndpoint()}, gateway)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	Describe("GetAppFromCf", func() {
+		It("should read backend from v2 app", func() {
+			responses["/v3/apps/app-guid/processes/web"] = `{"guid": "app-guid", "type": "web", "command": "./run", "instances": 2}`
+			app, err := finder.GetAppFromCf(context.Background(), "app-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(app.Diego).To(BeFalse())
+			Expect(app.Command).To(Equal("./run"))
+			Expect(app.InstanceCount).To(Equal(2))
+			Expect(app.EnableSSH).To(BeTrue())
+		})
+		It("should give no instances and no command to an app without web process", func() {
+			app, err := finder.GetAppFromCf(context.Background(), "app-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(app.Name).To(Equal("my-app"))
+			Expect(app.Command).To(BeEmpty())
+			Expect(app.InstanceCount).To(Equal(0))
+		})
+	})
+	Describe("GetServiceFromCf", func() {
+		It("should give plan and offering of service instance", func() {
+			responses["/v3/service_instances/svc-guid"] = `{
+				"guid": "svc-guid", "name": "my-db", "tags": ["sql"], "dashboard_url": "https://dashboard",
+				"last_operation": {"type": "create", "state": "succeeded"},
+				"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+				"included": {
+					"service_plans": [{"guid": "plan-guid", "name": "small", "relationships": {"service_offering": {"data": {"guid": "offering-guid"}}}}],
+					"service_offerings": [{"guid": "offering-guid", "name": "mysql"}]
+				}
+			}`
+			svc, err := finder.GetServiceFromCf(context.Background(), "svc-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(svc.GUID).To(Equal("svc-guid"))
+			Expect(svc.Name).To(Equal("my-db"))
+			Expect(svc.Tags).To(Equal([]string{"sql"}))
+			Expect(svc.DashboardURL).To(Equal("https://dashboard"))
+			Expect(svc.LastOperation.State).To(Equal("succeeded"))
+			Expect(svc.ServicePlan).To(Equal(models.ServicePlanFields{GUID: "plan-guid", Name: "small", ServiceOfferingGUID: "offering-guid"}))
+			Expect(svc.ServiceOffering).To(Equal(models.ServiceOfferingFields{GUID: "offering-guid", Label: "mysql"}))
+		})
+		It("should give an empty service instance when it doesn't exist", func() {
+			svc, err := finder.GetServiceFromCf(context.Background(), "unknown-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(svc.GUID).To(BeEmpty())
+		})
+	})
+	Describe("GetSpaceFromCf", func() {
+		It("should give organization, quota, ssh and running security groups of space", func() {
+			responses["/v3/spaces/space-guid"] = `{
+				"guid": "space-guid", "name": "dev",
+				"relationships": {"organization": {"data": {"guid": "org-guid"}}, "quota": {"data": {"guid": "quota-guid"}}},
+				"included": {"organizations": [{"guid": "org-guid", "name": "my-org"}]}
+			}`
+			responses["/v3/spaces/space-guid/features/ssh"] = `{"name": "ssh", "enabled": true}`
+			responses["/v3/security_groups"] = `{"resources": [{"guid": "sg-guid", "name": "public", "rules": [{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "443", "code": null}]}]}`
+			space, err := finder.GetSpaceFromCf(context.Background(), "space-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(space.SpaceFields).To(Equal(models.SpaceFields{GUID: "space-guid", Name: "dev", AllowSSH: true}))
+			Expect(space.Organization).To(Equal(models.OrganizationFields{GUID: "org-guid", Name: "my-org"}))
+			Expect(space.SpaceQuotaGUID).To(Equal("quota-guid"))
+			Expect(space.SecurityGroups).To(Equal([]models.SecurityGroupFields{{
+				GUID:  "sg-guid",
+				Name:  "public",
+				Rules: []map[string]interface{}{{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "443"}},
+			}}))
+		})
+	})
+	Describe("GetRouteFromCf", func() {
+		It("should give domain, space, apps and service instance of route", func() {
+			responses["/v3/routes/route-guid"] = `{
+				"guid": "route-guid", "host": "my-app", "path": "/api", "port": null,
+				"destinations": [{"app": {"guid": "app-guid"}}],
+				"relationships": {"space": {"data": {"guid": "space-guid"}}, "domain": {"data": {"guid": "domain-guid"}}},
+				"included": {
+					"domains": [{"guid": "domain-guid", "name": "example.com", "relationships": {"organization": {"data": null}}}],
+					"spaces": [{"guid": "space-guid", "name": "dev"}]
+				}
+			}`
+			responses["/v3/service_route_bindings"] = `{"resources": [], "included": {"service_instances": [{"guid": "svc-guid", "name": "my-route-service"}]}}`
+			route, err := finder.GetRouteFromCf(context.Background(), "route-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(route.GUID).To(Equal("route-guid"))
+			Expect(route.Host).To(Equal("my-app"))
+			Expect(route.Path).To(Equal("/api"))
+			Expect(route.Port).To(Equal(0))
+			Expect(route.Domain).To(Equal(models.DomainFields{GUID: "domain-guid", Name: "example.com", Shared: true}))
+			Expect(route.Space).To(Equal(models.SpaceFields{GUID: "space-guid", Name: "dev"}))
+			Expect(route.Apps).To(Equal([]models.ApplicationFields{{GUID: "app-guid"}}))
+			Expect(route.ServiceInstance).To(Equal(models.ServiceInstanceFields{GUID: "svc-guid", Name: "my-route-service"}))
+		})
+	})
+	Describe("GetQuotaFromCf", func() {
+		BeforeEach(func() {
+			quota := `{
+				"guid": "quota-guid", "name": "small",
+				"apps": {"total_memory_in_mb": 2048, "per_process_memory_in_mb": null, "total_instances": 10},
+				"services": {"paid_services_allowed": true, "total_service_instances": null},
+				"routes": {"total_routes": 5, "total_reserved_ports": null},
+				"relationships": {"organization": {"data": {"guid": "org-guid"}}}
+			}`
+			responses["/v3/organization_quotas/quota-guid"] = quota
+			responses["/v3/space_quotas/quota-guid"] = quota
+		})
+		It("should give -1 for unlimited values of an org quota", func() {
+			quota, err := finder.GetQuotaFromCf(context.Background(), "quota-guid", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quota).To(Equal(models.QuotaFields{
+				GUID:                    "quota-guid",
+				Name:                    "small",
+				MemoryLimit:             2048,
+				InstanceMemoryLimit:     -1,
+				RoutesLimit:             5,
+				ServicesLimit:           -1,
+				NonBasicServicesAllowed: true,
+				AppInstanceLimit:        10,
+				ReservedRoutePorts:      json.Number("-1"),
+			}))
+		})
+		It("should give organization of a space quota", func() {
+			quota, err := finder.GetQuotaFromCf(context.Background(), "quota-guid", false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quota).To(Equal(models.SpaceQuota{
+				GUID:                    "quota-guid",
+				Name:                    "small",
+				MemoryLimit:             2048,
+				InstanceMemoryLimit:     -1,
+				RoutesLimit:             5,
+				ServicesLimit:           -1,
+				NonBasicServicesAllowed: true,
+				OrgGUID:                 "org-guid",
+				AppInstanceLimit:        10,
+				ReservedRoutePortsLimit: json.Number("-1"),
+			}))
+		})
+	})
+	Describe("GetSecGroupFromCf", func() {
+		It("should give rules without null keys and running spaces", func() {
+			responses["/v3/security_groups/sg-guid"] = `{
+				"guid": "sg-guid", "name": "public",
+				"rules": [{"protocol": "icmp", "destination": "10.0.0.0/8", "type": 0, "code": 0, "ports": null, "log": null}],
+				"relationships": {"running_spaces": {"data": [{"guid": "space-guid"}]}}
+			}`
+			secGroup, err := finder.GetSecGroupFromCf(context.Background(), "sg-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secGroup.SecurityGroupFields).To(Equal(models.SecurityGroupFields{
+				GUID:  "sg-guid",
+				Name:  "public",
+				Rules: []map[string]interface{}{{"protocol": "icmp", "destination": "10.0.0.0/8", "type": float64(0), "code": float64(0)}},
+			}))
+			Expect(secGroup.Spaces).To(Equal([]models.Space{{SpaceFields: models.SpaceFields{GUID: "space-guid"}}}))
+		})
+	})
+})
@@ -10,7 +10,6 @@ import (
 	"crypto/tls"
 	"github.com/cloudfoundry/noaa/consumer"
 	"io/ioutil"
-	"net/http"
 	"time"
 )
 
@@ -20,9 +19,36 @@ type CloudFoundryGateways struct {
 	Config                 coreconfig.ReadWriter
 }
 
+// TokenRefresher refreshes tokens through uaa and gives an AuthError when it fails.
+// Access token from token file is used instead when it was rotated since the last refresh.
+type TokenRefresher struct {
+	refresher *noaabridge.TokenRefresher
+	config    coreconfig.Reader
+}
+
+func NewTokenRefresher(uaaClient *uaa.Client, config coreconfig.ReadWriter) *TokenRefresher {
+	return &TokenRefresher{
+		refresher: noaabridge.NewTokenRefresher(uaaClient, config),
+		config:    config,
+	}
+}
+func (t *TokenRefresher) RefreshAuthToken() (string, error) {
+	changed, err := reloadTokenFiles(t.config)
+	if err != nil {
+		return "", NewAuthError(err, t.config.UaaEndpoint())
+	}
+	if changed {
+		return t.config.AccessToken(), nil
+	}
+	token, err := t.refresher.RefreshAuthToken()
+	if err != nil {
+		return "", NewAuthError(err, t.config.UaaEndpoint())
+	}
+	return token, nil
+}
 func NewCloudControllerGateway(config coreconfig.ReadWriter, logger trace.Printer, uaaClient *uaa.Client) net.Gateway {
 	gw := net.NewCloudControllerGateway(config, time.Now, createUi(logger), logger, "5")
-	gw.SetTokenRefresher(noaabridge.NewTokenRefresher(uaaClient, config))
+	gw.SetTokenRefresher(NewTokenRefresher(uaaClient, config))
 	return gw
 }
 func createUi(logger trace.Printer) terminal.UI {
@@ -30,18 +56,16 @@ func createUi(logger trace.Printer) terminal.UI {
 }
 func NewUAAGateway(config coreconfig.ReadWriter, logger trace.Printer, uaaClient *uaa.Client) net.Gateway {
 	gw := net.NewUAAGateway(config, createUi(logger), logger, "5")
-	gw.SetTokenRefresher(noaabridge.NewTokenRefresher(uaaClient, config))
+	gw.SetTokenRefresher(NewTokenRefresher(uaaClient, config))
 	return gw
 }
-func NewNOAAClient(config coreconfig.ReadWriter, uaaClient *uaa.Client) *consumer.Consumer {
+func NewNOAAClient(config coreconfig.ReadWriter, uaaClient *uaa.Client, tlsConfig *tls.Config, proxy ProxyFunc) *consumer.Consumer {
 	client := consumer.New(
 		config.DopplerEndpoint(),
-		&tls.Config{
-			InsecureSkipVerify: config.IsSSLDisabled(),
-		},
-		http.ProxyFromEnvironment,
+		tlsConfig,
+		proxy,
 	)
-	client.RefreshTokenFrom(noaabridge.NewTokenRefresher(uaaClient, config))
+	client.RefreshTokenFrom(NewTokenRefresher(uaaClient, config))
 	return client
 }
 func NewCloudFoundryGateways(config coreconfig.ReadWriter, logger trace.Printer, uaaClient *uaa.Client) CloudFoundryGateways {
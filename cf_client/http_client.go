@@ -0,0 +1,281 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/api/cloudcontroller"
+	"code.cloudfoundry.org/cli/api/uaa"
+	"code.cloudfoundry.org/cli/cf/net"
+	"code.cloudfoundry.org/cli/cf/trace"
+	"crypto/tls"
+	"fmt"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"io"
+	"io/ioutil"
+	gonet "net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryTransport retries requests which failed on network errors or with a retryable status code
+type RetryTransport struct {
+	policy    common.RetryPolicy
+	transport http.RoundTripper
+}
+
+func NewRetryTransport(policy common.RetryPolicy, transport http.RoundTripper) *RetryTransport {
+	return &RetryTransport{
+		policy:    policy,
+		transport: transport,
+	}
+}
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if attempt+1 >= t.policy.MaxAttempts || !t.isRetryable(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		err = common.Sleep(req.Context(), t.policy.Backoff(attempt))
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+func (t *RetryTransport) isRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// body is a stream (e.g.: bits upload) and can't be sent again
+		return false
+	}
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+	if !t.policy.IsRetryableStatus(resp.StatusCode) {
+		return false
+	}
+	// cloud controller didn't process the request when it is rate limited or unavailable
+	return isIdempotent(req.Method) ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusServiceUnavailable
+}
+func isIdempotent(method string) bool {
+	return method != http.MethodPost && method != http.MethodPatch
+}
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	newReq := new(http.Request)
+	*newReq = *req
+	newReq.Body = body
+	return newReq, nil
+}
+
+// NewHTTPClient creates the http client shared by every client talking to Cloud Foundry,
+// tracer and journal can be nil to not trace or journal requests.
+func NewHTTPClient(providerCtx ProviderContext, config Config, tlsConfig *tls.Config, proxy ProxyFunc, tracer *RequestTracer, journal *MutationJournal) *http.Client {
+	var transport http.RoundTripper = &http.Transport{
+		Proxy: proxy,
+		DialContext: (&gonet.Dialer{
+			KeepAlive: 30 * time.Second,
+			Timeout:   config.Retry.DialTimeout,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   config.Retry.DialTimeout,
+		ResponseHeaderTimeout: config.Retry.RequestTimeout,
+	}
+	if tracer != nil {
+		// tracer is under retry transport to trace each attempt
+		transport = NewTraceTransport(tracer, transport)
+	}
+	// each attempt must wait for the limiter, limiter is shared by every client using this http client
+	transport = NewRateLimitTransport(
+		NewRateLimiter(config.MaxRequestsPerSecond, config.MaxConcurrentRequests),
+		transport,
+	)
+	transport = NewRetryTransport(config.Retry, transport)
+	transport = NewCCErrorTransport(transport)
+	if config.CacheLookups {
+		transport = NewCacheTransport(NewLookupCache(), transport)
+	}
+	if journal != nil {
+		// journal is above retry transport to record a change once with its final outcome
+		transport = NewJournalTransport(journal, transport)
+	}
+	if config.ReadOnly {
+		// refused requests must not be retried, traced as sent or invalidate cache
+		transport = NewReadOnlyTransport(transport)
+	}
+	// retry and rate limiter waits must be aborted too
+	transport = NewContextTransport(providerCtx, transport)
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// gatewayHTTPClients register http client to use by cf/net gateways for each host,
+// cf/net package only let us choose http client globally with net.NewHTTPClient.
+var gatewayHTTPClients = struct {
+	sync.RWMutex
+	byHost map[string]*http.Client
+	// byLogger gives http client of gateways which must not use the one of their host (e.g.: during a compensation)
+	byLogger map[*gatewayLogger]*http.Client
+}{byHost: make(map[string]*http.Client), byLogger: make(map[*gatewayLogger]*http.Client)}
+
+// gatewayLogger identifies gateways created with it, logger is the only thing
+// chosen per gateway that cf/net gives to net.NewHTTPClient.
+type gatewayLogger struct {
+	trace.Printer
+}
+
+// NewGatewayLogger gives a logger to create cf/net gateways which send their requests with httpClient
+// instead of the one registered for their host, release must be called when gateways are not used anymore.
+func NewGatewayLogger(logger trace.Printer, httpClient *http.Client) (gwLogger trace.Printer, release func()) {
+	l := &gatewayLogger{Printer: logger}
+	gatewayHTTPClients.Lock()
+	defer gatewayHTTPClients.Unlock()
+	gatewayHTTPClients.byLogger[l] = httpClient
+	net.NewHTTPClient = newGatewayHTTPClient
+	return l, func() {
+		gatewayHTTPClients.Lock()
+		defer gatewayHTTPClients.Unlock()
+		delete(gatewayHTTPClients.byLogger, l)
+	}
+}
+
+func RegisterGatewayHTTPClient(httpClient *http.Client, endpoints ...string) {
+	gatewayHTTPClients.Lock()
+	defer gatewayHTTPClients.Unlock()
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		gatewayHTTPClients.byHost[u.Host] = httpClient
+	}
+	net.NewHTTPClient = newGatewayHTTPClient
+}
+func newGatewayHTTPClient(tr *http.Transport, dumper net.RequestDumper) net.HTTPClientInterface {
+	c := &gatewayHTTPClient{
+		defaultClient: &http.Client{Transport: tr},
+		dumper:        dumper,
+	}
+	c.defaultClient.CheckRedirect = c.checkRedirect
+	return c
+}
+
+type gatewayHTTPClient struct {
+	defaultClient *http.Client
+	dumper        net.RequestDumper
+}
+
+func (c *gatewayHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	gatewayHTTPClients.RLock()
+	httpClient, ok := c.loggerHTTPClient()
+	if !ok {
+		httpClient, ok = gatewayHTTPClients.byHost[req.URL.Host]
+	}
+	gatewayHTTPClients.RUnlock()
+	if !ok {
+		return c.defaultClient.Do(req)
+	}
+	client := *httpClient
+	client.CheckRedirect = c.checkRedirect
+	return client.Do(req)
+}
+
+// loggerHTTPClient must be called with gatewayHTTPClients locked
+func (c *gatewayHTTPClient) loggerHTTPClient() (*http.Client, bool) {
+	for l, httpClient := range gatewayHTTPClients.byLogger {
+		// dumpers are only equal when made from the same logger
+		if c.dumper == net.NewRequestDumper(l) {
+			return httpClient, true
+		}
+	}
+	return nil, false
+}
+func (c *gatewayHTTPClient) ExecuteCheckRedirect(req *http.Request, via []*http.Request) error {
+	return c.checkRedirect(req, via)
+}
+func (c *gatewayHTTPClient) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > 1 {
+		return fmt.Errorf("stopped after 1 redirect")
+	}
+	prevReq := via[len(via)-1]
+	sameHost := req.URL.Host == via[0].URL.Host
+	for key, values := range prevReq.Header {
+		if key == "Content-Type" || key == "Content-Length" || (!sameHost && key == "Authorization") {
+			continue
+		}
+		req.Header.Set(key, strings.Join(values, ","))
+	}
+	c.dumper.DumpRequest(req)
+	return nil
+}
+func (c *gatewayHTTPClient) DumpRequest(req *http.Request) {
+	c.dumper.DumpRequest(req)
+}
+func (c *gatewayHTTPClient) DumpResponse(res *http.Response) {
+	c.dumper.DumpResponse(res)
+}
+
+// CCHTTPClientWrapper makes a ccv2 or ccv3 client use the given http client,
+// it must be the first wrapper given to the cc client.
+type CCHTTPClientWrapper struct {
+	httpClient *http.Client
+}
+
+func NewCCHTTPClientWrapper(httpClient *http.Client) *CCHTTPClientWrapper {
+	return &CCHTTPClientWrapper{httpClient}
+}
+func (w *CCHTTPClientWrapper) Make(request *cloudcontroller.Request, passedResponse *cloudcontroller.Response) error {
+	return fmt.Errorf("CCHTTPClientWrapper only replace the connection and can't make requests")
+}
+func (w *CCHTTPClientWrapper) Wrap(innerconnection cloudcontroller.Connection) cloudcontroller.Connection {
+	connection := &cloudcontroller.CloudControllerConnection{HTTPClient: w.httpClient}
+	// inner connection is the error wrapper of the cc client which wraps the default connection
+	if errWrapper, ok := innerconnection.(interface {
+		Wrap(cloudcontroller.Connection) cloudcontroller.Connection
+	}); ok {
+		return errWrapper.Wrap(connection)
+	}
+	return connection
+}
+
+// UAAHTTPClientWrapper makes an uaa client use the given http client,
+// it must be the first wrapper given to the uaa client.
+type UAAHTTPClientWrapper struct {
+	httpClient *http.Client
+}
+
+func NewUAAHTTPClientWrapper(httpClient *http.Client) *UAAHTTPClientWrapper {
+	return &UAAHTTPClientWrapper{httpClient}
+}
+func (w *UAAHTTPClientWrapper) Make(request *http.Request, passedResponse *uaa.Response) error {
+	return fmt.Errorf("UAAHTTPClientWrapper only replace the connection and can't make requests")
+}
+func (w *UAAHTTPClientWrapper) Wrap(innerconnection uaa.Connection) uaa.Connection {
+	httpClient := *w.httpClient
+	// uaa must not follow redirects, see uaa.NewConnection
+	httpClient.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	connection := &uaa.UAAConnection{HTTPClient: &httpClient}
+	// inner connection is the error wrapper of the uaa client which wraps the default connection
+	if errWrapper, ok := innerconnection.(uaa.ConnectionWrapper); ok {
+		return errWrapper.Wrap(connection)
+	}
+	return connection
+}
@@ -0,0 +1,116 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"code.cloudfoundry.org/cli/cf/net"
+	"code.cloudfoundry.org/cli/cf/terminal"
+	"code.cloudfoundry.org/cli/cf/trace"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+var _ = Describe("RetryTransport", func() {
+	var server *httptest.Server
+	var attempts int
+	var statusCodes []int
+	var client *http.Client
+	BeforeEach(func() {
+		attempts = 0
+		statusCodes = []int{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			statusCode := http.StatusOK
+			if attempts < len(statusCodes) {
+				statusCode = statusCodes[attempts]
+			}
+			attempts++
+			w.WriteHeader(statusCode)
+		}))
+		policy := common.DefaultRetryPolicy()
+		policy.InitialBackoff = time.Millisecond
+		client = &http.Client{Transport: NewRetryTransport(policy, http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("should retry on retryable status code", func() {
+		statusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable}
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts).To(Equal(3))
+	})
+	It("should give last response after max attempts", func() {
+		statusCodes = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(attempts).To(Equal(3))
+	})
+	It("should not retry on non retryable status code", func() {
+		statusCodes = []int{http.StatusInternalServerError}
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(attempts).To(Equal(1))
+	})
+	It("should not retry a post on bad gateway", func() {
+		statusCodes = []int{http.StatusBadGateway}
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(attempts).To(Equal(1))
+	})
+	It("should retry a post when rate limited", func() {
+		statusCodes = []int{http.StatusTooManyRequests}
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts).To(Equal(2))
+	})
+})
+
+// clientNameTransport tells server which http client sent a request
+type clientNameTransport struct {
+	name string
+}
+
+func (t clientNameTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Client-Name", t.name)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+var _ = Describe("NewGatewayLogger", func() {
+	var server *httptest.Server
+	var clientNames []string
+	BeforeEach(func() {
+		clientNames = []string{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientNames = append(clientNames, r.Header.Get("X-Client-Name"))
+			w.Write([]byte(`{}`))
+		}))
+		RegisterGatewayHTTPClient(&http.Client{Transport: clientNameTransport{"host"}}, server.URL)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("should make gateways created with it use their own http client", func() {
+		repository, hostGateway := newTestGateway(server.URL)
+		logger := trace.NewLogger(ioutil.Discard, false, "", "")
+		gwLogger, release := NewGatewayLogger(logger, &http.Client{Transport: clientNameTransport{"dedicated"}})
+		ui := terminal.NewUI(ioutil.NopCloser(nil), ioutil.Discard, terminal.NewTeePrinter(ioutil.Discard), gwLogger)
+		gateway := net.NewCloudControllerGateway(repository, time.Now, ui, gwLogger, "5")
+
+		Expect(gateway.GetResource(server.URL+"/v2/info", &struct{}{})).To(Succeed())
+		Expect(hostGateway.GetResource(server.URL+"/v2/info", &struct{}{})).To(Succeed())
+		release()
+		Expect(gateway.GetResource(server.URL+"/v2/info", &struct{}{})).To(Succeed())
+		Expect(clientNames).To(Equal([]string{"dedicated", "host", "host"}))
+	})
+})
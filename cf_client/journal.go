@@ -0,0 +1,172 @@
+package cf_client
+
+import (
+	"encoding/json"
+	"github.com/mitchellh/go-homedir"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MutationKindRequest  = "request"
+	MutationKindResource = "resource"
+)
+
+var guidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// MutationRecord is a json line written in journal file for each change made on Cloud Foundry,
+// a record of kind request is a call made to cloud controller,
+// a record of kind resource is a create, update or delete of a terraform resource.
+type MutationRecord struct {
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Resource   string    `json:"resource,omitempty"`
+	Operation  string    `json:"operation,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	Endpoint   string    `json:"endpoint,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	GUIDBefore string    `json:"guid_before,omitempty"`
+	GUIDAfter  string    `json:"guid_after,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// MutationJournal appends changes made on Cloud Foundry as json lines in a file,
+// a nil journal records nothing.
+type MutationJournal struct {
+	writer io.Writer
+	mutex  *sync.Mutex
+}
+
+func NewMutationJournal(path string) (*MutationJournal, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewMutationJournalWithWriter(f), nil
+}
+func NewMutationJournalWithWriter(writer io.Writer) *MutationJournal {
+	return &MutationJournal{
+		writer: writer,
+		mutex:  new(sync.Mutex),
+	}
+}
+
+// Close closes journal file, it does nothing when journal doesn't write in a file
+func (j *MutationJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	closer, ok := j.writer.(io.Closer)
+	if !ok {
+		return nil
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return closer.Close()
+}
+func (j *MutationJournal) Record(record MutationRecord) {
+	if j == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.writer.Write(append(b, '\n'))
+}
+
+// JournalTransport records in a MutationJournal every request which changes Cloud Foundry,
+// lookups and uaa authentication are not recorded.
+type JournalTransport struct {
+	journal   *MutationJournal
+	transport http.RoundTripper
+}
+
+func NewJournalTransport(journal *MutationJournal, transport http.RoundTripper) *JournalTransport {
+	return &JournalTransport{
+		journal:   journal,
+		transport: transport,
+	}
+}
+func (t *JournalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		return t.transport.RoundTrip(req)
+	}
+	endpoint := *req.URL
+	endpoint.RawQuery = ""
+	endpoint.User = nil
+	record := MutationRecord{
+		Time:       time.Now(),
+		Kind:       MutationKindRequest,
+		Resource:   resourceAddressFromContext(req.Context()),
+		Method:     req.Method,
+		Endpoint:   endpoint.String(),
+		GUIDBefore: guidFromPath(req.URL.Path),
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		record.Error = err.Error()
+		t.journal.Record(record)
+		return resp, err
+	}
+	record.Status = resp.StatusCode
+	record.GUIDAfter = guidFromResponse(resp)
+	if record.GUIDAfter == "" && req.Method != http.MethodDelete && resp.StatusCode < http.StatusBadRequest {
+		record.GUIDAfter = record.GUIDBefore
+	}
+	t.journal.Record(record)
+	return resp, nil
+}
+
+// guidFromPath gives the guid of the first resource targeted by path (e.g.: app guid in /v2/apps/:guid/bits)
+func guidFromPath(path string) string {
+	for _, part := range strings.Split(path, "/") {
+		if guidRegex.MatchString(part) {
+			return part
+		}
+	}
+	return ""
+}
+
+// guidFromResponse gives guid of resource returned by cloud controller v2 (metadata.guid) or v3 (guid),
+// it gives nothing for an async v2 job (e.g.: on PUT /v2/apps/:guid/bits)
+func guidFromResponse(resp *http.Response) string {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") || resp.ContentLength > maxTracedBodyLen {
+		return ""
+	}
+	b, complete, err := peekResponseBody(resp)
+	if err != nil || !complete {
+		return ""
+	}
+	var content struct {
+		GUID     string `json:"guid"`
+		Metadata struct {
+			GUID string `json:"guid"`
+			URL  string `json:"url"`
+		} `json:"metadata"`
+	}
+	if json.Unmarshal(b, &content) != nil {
+		return ""
+	}
+	if strings.HasPrefix(content.Metadata.URL, "/v2/jobs/") {
+		return ""
+	}
+	if content.Metadata.GUID != "" {
+		return content.Metadata.GUID
+	}
+	return content.GUID
+}
@@ -0,0 +1,108 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe("JournalTransport", func() {
+	const appGUID = "8c3e7f3a-1d9b-4b6e-9f0a-2b1c3d4e5f60"
+	var server *httptest.Server
+	var buf *bytes.Buffer
+	var client *http.Client
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/bits") {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"metadata":{"guid":"1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b","url":"/v2/jobs/1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"},"entity":{"status":"queued"}}`))
+				return
+			}
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"metadata":{"guid":"` + appGUID + `"}}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		buf = new(bytes.Buffer)
+		client = &http.Client{Transport: NewJournalTransport(NewMutationJournalWithWriter(buf), http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	records := func() []MutationRecord {
+		result := make([]MutationRecord, 0)
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record MutationRecord
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			result = append(result, record)
+		}
+		return result
+	}
+	It("should not record lookups", func() {
+		resp, err := client.Get(server.URL + "/v2/apps/" + appGUID)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(records()).To(BeEmpty())
+	})
+	It("should record guid returned by cloud controller on creation", func() {
+		resp, err := client.Post(server.URL+"/v2/apps?async=true", "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		r := records()
+		Expect(r).To(HaveLen(1))
+		Expect(r[0].Kind).To(Equal(MutationKindRequest))
+		Expect(r[0].Method).To(Equal(http.MethodPost))
+		Expect(r[0].Endpoint).To(Equal(server.URL + "/v2/apps"))
+		Expect(r[0].Status).To(Equal(http.StatusCreated))
+		Expect(r[0].GUIDBefore).To(BeEmpty())
+		Expect(r[0].GUIDAfter).To(Equal(appGUID))
+	})
+	It("should record guid targeted by endpoint on deletion", func() {
+		req, err := http.NewRequest(http.MethodDelete, server.URL+"/v2/apps/"+appGUID, nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		r := records()
+		Expect(r).To(HaveLen(1))
+		Expect(r[0].GUIDBefore).To(Equal(appGUID))
+		Expect(r[0].GUIDAfter).To(BeEmpty())
+	})
+	It("should record guid targeted by endpoint when cloud controller answers with an async job", func() {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/v2/apps/"+appGUID+"/bits?async=true", strings.NewReader("bits"))
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		r := records()
+		Expect(r).To(HaveLen(1))
+		Expect(r[0].GUIDBefore).To(Equal(appGUID))
+		Expect(r[0].GUIDAfter).To(Equal(appGUID))
+	})
+	It("should record address of resource doing the request", func() {
+		ctx := WithResourceAddress(context.Background(), func() string {
+			return "cloudfoundry_app." + appGUID
+		})
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/v2/apps", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Do(req.WithContext(ctx))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		r := records()
+		Expect(r).To(HaveLen(1))
+		Expect(r[0].Resource).To(Equal("cloudfoundry_app." + appGUID))
+	})
+})
@@ -0,0 +1,50 @@
+package cf_client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// PermissionError is given in non admin mode when an operation needs Cloud Foundry admin permissions,
+// it is given instead of a partial result built from what user can see.
+type PermissionError struct {
+	Operation string
+	// Hint explains how to do it without admin permissions, it can be empty
+	Hint string
+}
+
+func (e PermissionError) Error() string {
+	message := fmt.Sprintf(
+		"%s needs Cloud Foundry admin permissions, it can't be done when 'non_admin' is set to true",
+		e.Operation,
+	)
+	if e.Hint != "" {
+		message += ": " + e.Hint
+	}
+	return message
+}
+
+// RequireAdmin gives a PermissionError for operation when provider is in non admin mode
+func (c Config) RequireAdmin(operation, hint string) error {
+	if !c.NonAdmin {
+		return nil
+	}
+	return PermissionError{
+		Operation: operation,
+		Hint:      hint,
+	}
+}
+
+// ExplainForbidden tells which permissions are missing when Cloud Controller refused a request,
+// other errors are given unchanged.
+func ExplainForbidden(err error) error {
+	ccErr, ok := err.(*CCError)
+	if !ok || ccErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+	ccErr.Description = fmt.Sprintf(
+		"%s: user must be SpaceDeveloper of the space or OrgManager of the org owning this object in non admin mode",
+		ccErr.Description,
+	)
+	return ccErr
+}
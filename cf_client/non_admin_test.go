@@ -0,0 +1,37 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("NonAdmin", func() {
+	Describe("RequireAdmin", func() {
+		It("should let operation be done by an admin", func() {
+			config := Config{}
+			Expect(config.RequireAdmin("listing every org", "")).To(Succeed())
+		})
+		It("should explain which operation needs admin in non admin mode", func() {
+			config := Config{NonAdmin: true}
+			err := config.RequireAdmin("listing every org", "use an org data source")
+			Expect(err).To(BeAssignableToTypeOf(PermissionError{}))
+			Expect(err.Error()).To(Equal("listing every org needs Cloud Foundry admin permissions, it can't be done when 'non_admin' is set to true: use an org data source"))
+		})
+	})
+	Describe("ExplainForbidden", func() {
+		It("should tell which role is missing when Cloud Controller refused request", func() {
+			err := ExplainForbidden(&CCError{Description: "You are not authorized to perform the requested action", StatusCode: http.StatusForbidden})
+			Expect(err.Error()).To(ContainSubstring("You are not authorized to perform the requested action: user must be SpaceDeveloper"))
+		})
+		It("should give other errors unchanged", func() {
+			notFound := &CCError{Description: "not found", StatusCode: http.StatusNotFound}
+			Expect(ExplainForbidden(notFound)).To(Equal(notFound))
+			other := errors.New("other")
+			Expect(ExplainForbidden(other)).To(Equal(other))
+		})
+	})
+})
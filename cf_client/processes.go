@@ -0,0 +1,212 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
+	"code.cloudfoundry.org/cli/cf/errors"
+	"code.cloudfoundry.org/cli/cf/net"
+	"context"
+	"fmt"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"time"
+)
+
+const (
+	ProcessTypeWeb = "web"
+
+	ProcessInstanceRunning = "RUNNING"
+	ProcessInstanceCrashed = "CRASHED"
+)
+
+// instanceRestartTimeout is the time given to a restarted instance to run again
+const instanceRestartTimeout = 15 * time.Minute
+
+// Process is a cloud controller v3 process type of an app (e.g.: web, worker, clock),
+// ccv3 client vendored doesn't send command and doesn't read health check timeout.
+type Process struct {
+	GUID                string
+	Type                string
+	Command             string
+	Instances           int
+	MemoryInMB          int64
+	DiskInMB            int64
+	HealthCheckType     string
+	HealthCheckEndpoint string
+	// HealthCheckTimeout is the time given to an instance to become healthy, cloud controller default is used when 0
+	HealthCheckTimeout int
+}
+
+// ProcessInstance is the state of an instance of a process type, uptime is in seconds
+type ProcessInstance struct {
+	Index  int    `json:"index"`
+	State  string `json:"state"`
+	Uptime int    `json:"uptime"`
+}
+
+func (p v3Process) ToProcess() Process {
+	process := Process{
+		GUID:            p.GUID,
+		Type:            p.Type,
+		Instances:       p.Instances,
+		MemoryInMB:      p.MemoryInMb,
+		DiskInMB:        p.DiskInMb,
+		HealthCheckType: p.HealthCheck.Type,
+	}
+	if p.Command != nil {
+		process.Command = *p.Command
+	}
+	if p.HealthCheck.Data.Endpoint != nil {
+		process.HealthCheckEndpoint = *p.HealthCheck.Data.Endpoint
+	}
+	if p.HealthCheck.Data.Timeout != nil {
+		process.HealthCheckTimeout = *p.HealthCheck.Data.Timeout
+	}
+	return process
+}
+
+// ProcessRepository manages process types of an app, they are created by cloud controller from the droplet (e.g.: Procfile),
+// they can only be updated and scaled.
+type ProcessRepository interface {
+	// GetByType gives a process with an empty GUID when app doesn't have this process type
+	GetByType(appGuid, processType string) (Process, error)
+	// Update changes command and health check of process, command is left unchanged when empty
+	Update(process Process) error
+	// Scale changes instances, memory and disk of process without restaging app
+	Scale(process Process) error
+	// GetInstances gives state of every instance of process
+	GetInstances(processGuid string) ([]ProcessInstance, error)
+	// RestartInstance stops an instance, cloud controller starts a new one with the same index
+	RestartInstance(processGuid string, index int) error
+}
+
+type CloudControllerProcessRepository struct {
+	config    coreconfig.Reader
+	ccGateway net.Gateway
+}
+
+func NewCloudControllerProcessRepository(config coreconfig.Reader, ccGateway net.Gateway) *CloudControllerProcessRepository {
+	return &CloudControllerProcessRepository{
+		config:    config,
+		ccGateway: ccGateway,
+	}
+}
+func (r CloudControllerProcessRepository) GetByType(appGuid, processType string) (Process, error) {
+	process := v3Process{}
+	err := r.ccGateway.GetResource(
+		fmt.Sprintf("%s/v3/apps/%s/processes/%s", r.config.APIEndpoint(), appGuid, processType),
+		&process,
+	)
+	if _, ok := err.(*errors.HTTPNotFoundError); ok {
+		return Process{}, nil
+	}
+	if err != nil {
+		return Process{}, err
+	}
+	return process.ToProcess(), nil
+}
+func (r CloudControllerProcessRepository) Update(process Process) error {
+	data := map[string]interface{}{
+		"endpoint": nil,
+		"timeout":  nil,
+	}
+	if process.HealthCheckEndpoint != "" {
+		data["endpoint"] = process.HealthCheckEndpoint
+	}
+	if process.HealthCheckTimeout != 0 {
+		data["timeout"] = process.HealthCheckTimeout
+	}
+	body := map[string]interface{}{
+		"health_check": map[string]interface{}{
+			"type": process.HealthCheckType,
+			"data": data,
+		},
+	}
+	if process.Command != "" {
+		body["command"] = process.Command
+	}
+	return sendV3Request(r.config, r.ccGateway, "PATCH", "/v3/processes/"+process.GUID, body, &v3Process{})
+}
+func (r CloudControllerProcessRepository) Scale(process Process) error {
+	body := map[string]interface{}{
+		"instances":    process.Instances,
+		"memory_in_mb": process.MemoryInMB,
+		"disk_in_mb":   process.DiskInMB,
+	}
+	return sendV3Request(r.config, r.ccGateway, "POST", fmt.Sprintf("/v3/processes/%s/actions/scale", process.GUID), body, &v3Process{})
+}
+func (r CloudControllerProcessRepository) GetInstances(processGuid string) ([]ProcessInstance, error) {
+	stats := struct {
+		Resources []ProcessInstance `json:"resources"`
+	}{}
+	err := r.ccGateway.GetResource(
+		fmt.Sprintf("%s/v3/processes/%s/stats", r.config.APIEndpoint(), processGuid),
+		&stats,
+	)
+	if err != nil {
+		return []ProcessInstance{}, err
+	}
+	return stats.Resources, nil
+}
+func (r CloudControllerProcessRepository) RestartInstance(processGuid string, index int) error {
+	return sendV3Request(r.config, r.ccGateway, "DELETE", fmt.Sprintf("/v3/processes/%s/instances/%d", processGuid, index), nil, nil)
+}
+
+// UpdateProcess makes current process match desired one, it is only updated and scaled when needed.
+// Instances running before a change of command, health check, memory or disk are restarted one by one to use it,
+// an instance is restarted once the previous one runs again.
+func UpdateProcess(ctx context.Context, repo ProcessRepository, current, desired Process, pollingInterval time.Duration) error {
+	desired.GUID = current.GUID
+	settingsChanged := (desired.Command != "" && desired.Command != current.Command) ||
+		desired.HealthCheckType != current.HealthCheckType ||
+		desired.HealthCheckEndpoint != current.HealthCheckEndpoint ||
+		desired.HealthCheckTimeout != current.HealthCheckTimeout
+	if settingsChanged {
+		err := repo.Update(desired)
+		if err != nil {
+			return err
+		}
+	}
+	// running instances keep their limits until they are restarted
+	limitsChanged := desired.MemoryInMB != current.MemoryInMB || desired.DiskInMB != current.DiskInMB
+	if desired.Instances != current.Instances || limitsChanged {
+		err := repo.Scale(desired)
+		if err != nil {
+			return err
+		}
+	}
+	if !settingsChanged && !limitsChanged {
+		return nil
+	}
+	// instances added by scale already use new settings and limits
+	for index := 0; index < current.Instances && index < desired.Instances; index++ {
+		err := restartInstance(ctx, repo, desired, index, pollingInterval)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func restartInstance(ctx context.Context, repo ProcessRepository, process Process, index int, pollingInterval time.Duration) error {
+	restartTime := time.Now()
+	err := repo.RestartInstance(process.GUID, index)
+	if err != nil {
+		return err
+	}
+	return common.PollingWithTimeout(ctx, func() (bool, error) {
+		instances, err := repo.GetInstances(process.GUID)
+		if err != nil {
+			return true, err
+		}
+		for _, instance := range instances {
+			if instance.Index != index {
+				continue
+			}
+			if instance.State == ProcessInstanceCrashed {
+				return true, fmt.Errorf("Instance %d of process type %s crashed after its restart", index, process.Type)
+			}
+			// instance stopped may still be seen running, new one has started after restart
+			started := time.Duration(instance.Uptime)*time.Second <= time.Since(restartTime)+time.Second
+			return instance.State == ProcessInstanceRunning && started, nil
+		}
+		return false, nil
+	}, pollingInterval, instanceRestartTimeout)
+}
@@ -0,0 +1,201 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"context"
+	"encoding/json"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+var _ = Describe("Processes", func() {
+	var server *httptest.Server
+	var requests []string
+	var bodies map[string]map[string]interface{}
+	// instanceStates gives states returned by stats of each instance index, last state is kept
+	var instanceStates map[int][]string
+	var restartedAt map[int]time.Time
+	var repo ProcessRepository
+	BeforeEach(func() {
+		requests = []string{}
+		bodies = map[string]map[string]interface{}{}
+		instanceStates = map[int][]string{}
+		restartedAt = map[int]time.Time{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			request := r.Method + " " + r.URL.Path
+			requests = append(requests, request)
+			if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+				body := map[string]interface{}{}
+				json.Unmarshal(b, &body)
+				bodies[request] = body
+			}
+			switch {
+			case request == "GET /v3/apps/app-guid/processes/worker":
+				w.Write([]byte(`{"guid": "process-guid", "type": "worker", "command": "./work", "instances": 2, "memory_in_mb": 256, "disk_in_mb": 512, "health_check": {"type": "port", "data": {"timeout": 60, "endpoint": null}}}`))
+			case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/v3/processes/process-guid/instances/"):
+				var index int
+				fmt.Sscanf(r.URL.Path, "/v3/processes/process-guid/instances/%d", &index)
+				restartedAt[index] = time.Now()
+				w.WriteHeader(http.StatusNoContent)
+			case request == "GET /v3/processes/process-guid/stats":
+				resources := make([]ProcessInstance, 0)
+				for index := 0; index < 2; index++ {
+					instance := ProcessInstance{Index: index, State: ProcessInstanceRunning, Uptime: 3600}
+					if _, ok := restartedAt[index]; ok {
+						instance.State = instanceStates[index][0]
+						instance.Uptime = 0
+						if len(instanceStates[index]) > 1 {
+							instanceStates[index] = instanceStates[index][1:]
+						}
+					}
+					resources = append(resources, instance)
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"resources": resources})
+			case request == "PATCH /v3/processes/process-guid", request == "POST /v3/processes/process-guid/actions/scale":
+				w.Write([]byte(`{"guid": "process-guid"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Process not found"}]}`))
+			}
+		}))
+		repository, gateway := newTestGateway(server.URL)
+		repo = NewCloudControllerProcessRepository(repository, gateway)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	Describe("GetByType", func() {
+		It("should give process of app", func() {
+			process, err := repo.GetByType("app-guid", "worker")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(process).To(Equal(Process{
+				GUID:               "process-guid",
+				Type:               "worker",
+				Command:            "./work",
+				Instances:          2,
+				MemoryInMB:         256,
+				DiskInMB:           512,
+				HealthCheckType:    "port",
+				HealthCheckTimeout: 60,
+			}))
+		})
+		It("should give a process with an empty guid when app doesn't have this process type", func() {
+			process, err := repo.GetByType("app-guid", "clock")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(process.GUID).To(BeEmpty())
+		})
+	})
+	Describe("Update", func() {
+		It("should send command and health check", func() {
+			err := repo.Update(Process{GUID: "process-guid", Command: "./run", HealthCheckType: "http", HealthCheckEndpoint: "/health"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bodies["PATCH /v3/processes/process-guid"]).To(Equal(map[string]interface{}{
+				"command": "./run",
+				"health_check": map[string]interface{}{
+					"type": "http",
+					"data": map[string]interface{}{"endpoint": "/health", "timeout": nil},
+				},
+			}))
+		})
+		It("should leave command unchanged when it is empty", func() {
+			err := repo.Update(Process{GUID: "process-guid", HealthCheckType: "process"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bodies["PATCH /v3/processes/process-guid"]).ToNot(HaveKey("command"))
+		})
+	})
+	Describe("Scale", func() {
+		It("should send instances, memory and disk", func() {
+			err := repo.Scale(Process{GUID: "process-guid", Instances: 3, MemoryInMB: 512, DiskInMB: 1024})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bodies["POST /v3/processes/process-guid/actions/scale"]).To(Equal(map[string]interface{}{
+				"instances":    float64(3),
+				"memory_in_mb": float64(512),
+				"disk_in_mb":   float64(1024),
+			}))
+		})
+	})
+	Describe("UpdateProcess", func() {
+		var current Process
+		BeforeEach(func() {
+			var err error
+			current, err = repo.GetByType("app-guid", "worker")
+			Expect(err).ToNot(HaveOccurred())
+			requests = []string{}
+		})
+		It("should do nothing when process is unchanged", func() {
+			err := UpdateProcess(context.Background(), repo, current, current, time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(BeEmpty())
+		})
+		It("should only scale process without restarting instances when only instances change", func() {
+			desired := current
+			desired.Instances = 3
+			err := UpdateProcess(context.Background(), repo, current, desired, time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{"POST /v3/processes/process-guid/actions/scale"}))
+		})
+		It("should restart instances one by one after scale when memory or disk change", func() {
+			instanceStates[0] = []string{ProcessInstanceRunning}
+			instanceStates[1] = []string{ProcessInstanceRunning}
+			desired := current
+			desired.MemoryInMB = 512
+			desired.DiskInMB = 1024
+			err := UpdateProcess(context.Background(), repo, current, desired, time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{
+				"POST /v3/processes/process-guid/actions/scale",
+				"DELETE /v3/processes/process-guid/instances/0",
+				"GET /v3/processes/process-guid/stats",
+				"DELETE /v3/processes/process-guid/instances/1",
+				"GET /v3/processes/process-guid/stats",
+			}))
+		})
+		It("should restart instances one by one when command changes", func() {
+			instanceStates[0] = []string{"STARTING", ProcessInstanceRunning}
+			instanceStates[1] = []string{"STARTING", ProcessInstanceRunning}
+			desired := current
+			desired.Command = "./work --new"
+			err := UpdateProcess(context.Background(), repo, current, desired, time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{
+				"PATCH /v3/processes/process-guid",
+				"DELETE /v3/processes/process-guid/instances/0",
+				"GET /v3/processes/process-guid/stats",
+				"GET /v3/processes/process-guid/stats",
+				"DELETE /v3/processes/process-guid/instances/1",
+				"GET /v3/processes/process-guid/stats",
+				"GET /v3/processes/process-guid/stats",
+			}))
+		})
+		It("should only restart instances which were running before scale", func() {
+			instanceStates[0] = []string{ProcessInstanceRunning}
+			desired := current
+			desired.HealthCheckType = "process"
+			desired.Instances = 1
+			err := UpdateProcess(context.Background(), repo, current, desired, time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{
+				"PATCH /v3/processes/process-guid",
+				"POST /v3/processes/process-guid/actions/scale",
+				"DELETE /v3/processes/process-guid/instances/0",
+				"GET /v3/processes/process-guid/stats",
+			}))
+		})
+		It("should stop restarting instances when a restarted instance crashes", func() {
+			instanceStates[0] = []string{ProcessInstanceCrashed}
+			desired := current
+			desired.Command = "./work --new"
+			err := UpdateProcess(context.Background(), repo, current, desired, time.Millisecond)
+			Expect(err).To(MatchError(ContainSubstring("Instance 0 of process type worker crashed")))
+			Expect(requests).ToNot(ContainElement("DELETE /v3/processes/process-guid/instances/1"))
+		})
+	})
+})
@@ -0,0 +1,45 @@
+package cf_client
+
+import (
+	"fmt"
+	"path"
+)
+
+const (
+	ProtectedOrgs     = "orgs"
+	ProtectedSpaces   = "spaces"
+	ProtectedApps     = "apps"
+	ProtectedServices = "services"
+	ProtectedDomains  = "domains"
+)
+
+// ProtectedObjects are names or guid patterns (e.g.: system, *-platform) by kind of objects
+// which can't be deleted, replaced or renamed by the provider.
+type ProtectedObjects map[string][]string
+
+// Validate gives an error on the first malformed pattern
+func (p ProtectedObjects) Validate() error {
+	for kind, patterns := range p {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("malformed pattern '%s' in protected %s: %s", pattern, kind, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// Match gives the first pattern of a kind which matches one of the values (name or guid of an object)
+func (p ProtectedObjects) Match(kind string, values ...string) (string, bool) {
+	for _, pattern := range p[kind] {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, value); ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,37 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProtectedObjects", func() {
+	protected := ProtectedObjects{
+		ProtectedOrgs:   []string{"system", "1c2b7a3e-*"},
+		ProtectedSpaces: []string{"platform-*"},
+	}
+	It("should match an object by name or guid pattern", func() {
+		pattern, ok := protected.Match(ProtectedOrgs, "system", "guid")
+		Expect(ok).To(BeTrue())
+		Expect(pattern).To(Equal("system"))
+
+		pattern, ok = protected.Match(ProtectedOrgs, "my-org", "1c2b7a3e-0000-0000-0000-000000000000")
+		Expect(ok).To(BeTrue())
+		Expect(pattern).To(Equal("1c2b7a3e-*"))
+
+		_, ok = protected.Match(ProtectedSpaces, "platform-logs")
+		Expect(ok).To(BeTrue())
+	})
+	It("should not match other objects", func() {
+		_, ok := protected.Match(ProtectedOrgs, "my-org", "")
+		Expect(ok).To(BeFalse())
+		_, ok = protected.Match(ProtectedApps, "system")
+		Expect(ok).To(BeFalse())
+	})
+	It("should refuse malformed patterns", func() {
+		Expect(ProtectedObjects{ProtectedApps: []string{"[app"}}.Validate()).ToNot(Succeed())
+		Expect(protected.Validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,110 @@
+package cf_client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFunc gives the proxy to use for a request, nil url means no proxy
+type ProxyFunc func(*http.Request) (*url.URL, error)
+
+type proxyConfig struct {
+	httpProxy  *url.URL
+	httpsProxy *url.URL
+	noProxy    []string
+}
+
+// NewProxyFunc creates the proxy func shared by every transport of the provider
+// from proxies given in config, environment variables are used when none are given.
+func NewProxyFunc(config Config) (ProxyFunc, error) {
+	if config.HTTPProxy == "" && config.HTTPSProxy == "" && config.NoProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	httpProxy, err := parseProxy(config.HTTPProxy)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid 'http_proxy': %s", err.Error())
+	}
+	httpsProxy, err := parseProxy(config.HTTPSProxy)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid 'https_proxy': %s", err.Error())
+	}
+	noProxy := make([]string, 0)
+	for _, elem := range strings.Split(config.NoProxy, ",") {
+		elem = strings.ToLower(strings.TrimSpace(elem))
+		if elem != "" {
+			noProxy = append(noProxy, elem)
+		}
+	}
+	p := proxyConfig{
+		httpProxy:  httpProxy,
+		httpsProxy: httpsProxy,
+		noProxy:    noProxy,
+	}
+	return p.proxy, nil
+}
+func parseProxy(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("no host found in '%s'", proxy)
+	}
+	return proxyURL, nil
+}
+func (p proxyConfig) proxy(req *http.Request) (*url.URL, error) {
+	proxyURL := p.httpProxy
+	if req.URL.Scheme == "https" || req.URL.Scheme == "wss" {
+		proxyURL = p.httpsProxy
+	}
+	if proxyURL == nil || !p.useProxy(req.URL.Host) {
+		return nil, nil
+	}
+	return proxyURL, nil
+}
+func (p proxyConfig) useProxy(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+	for _, elem := range p.noProxy {
+		if elem == "*" {
+			return false
+		}
+		if _, cidr, err := net.ParseCIDR(elem); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return false
+			}
+			continue
+		}
+		if strings.Contains(elem, ":") && net.ParseIP(elem) == nil {
+			// entry with a port only matches this port
+			if elem == strings.ToLower(hostport) {
+				return false
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(elem, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	}
+	return true
+}
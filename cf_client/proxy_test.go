@@ -0,0 +1,50 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("NewProxyFunc", func() {
+	proxyFor := func(proxy ProxyFunc, rawURL string) string {
+		req, err := http.NewRequest("GET", rawURL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		proxyURL, err := proxy(req)
+		Expect(err).ToNot(HaveOccurred())
+		if proxyURL == nil {
+			return ""
+		}
+		return proxyURL.String()
+	}
+	It("should use proxy according to the scheme", func() {
+		proxy, err := NewProxyFunc(Config{
+			HTTPProxy:  "http://proxy.my.corp:3128",
+			HTTPSProxy: "secure-proxy.my.corp:3129",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxyFor(proxy, "http://api.my.cf.com")).To(Equal("http://proxy.my.corp:3128"))
+		Expect(proxyFor(proxy, "https://api.my.cf.com")).To(Equal("http://secure-proxy.my.corp:3129"))
+		Expect(proxyFor(proxy, "wss://doppler.my.cf.com:443")).To(Equal("http://secure-proxy.my.corp:3129"))
+	})
+	It("should not use proxy for hosts in no_proxy", func() {
+		proxy, err := NewProxyFunc(Config{
+			HTTPSProxy: "http://proxy.my.corp:3128",
+			NoProxy:    ".internal.corp, git.my.corp, 10.0.0.0/8, broker.my.corp:8443",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxyFor(proxy, "https://api.internal.corp")).To(BeEmpty())
+		Expect(proxyFor(proxy, "https://git.my.corp/org/repo")).To(BeEmpty())
+		Expect(proxyFor(proxy, "https://10.1.2.3:8080")).To(BeEmpty())
+		Expect(proxyFor(proxy, "https://broker.my.corp:8443")).To(BeEmpty())
+		Expect(proxyFor(proxy, "https://broker.my.corp")).ToNot(BeEmpty())
+		Expect(proxyFor(proxy, "https://localhost:8080")).To(BeEmpty())
+		Expect(proxyFor(proxy, "https://github.com")).To(Equal("http://proxy.my.corp:3128"))
+	})
+	It("should return an error on invalid proxy", func() {
+		_, err := NewProxyFunc(Config{HTTPProxy: "http://"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,163 @@
+package cf_client
+
+import (
+	"context"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRateLimitPause caps the pause asked by cloud controller rate limit headers
+// to not hang a terraform run when reset is far away.
+const maxRateLimitPause = 5 * time.Minute
+
+// RateLimiter is a token bucket limiting requests per second coupled with a semaphore
+// limiting requests in flight, 0 disables a limit.
+// It also pauses requests when cloud controller says that rate limit has been reached.
+type RateLimiter struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	inFlight    chan struct{}
+	mutex       *sync.Mutex
+}
+
+func NewRateLimiter(requestsPerSecond float64, maxConcurrent int) *RateLimiter {
+	burst := requestsPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+	var inFlight chan struct{}
+	if maxConcurrent > 0 {
+		inFlight = make(chan struct{}, maxConcurrent)
+	}
+	return &RateLimiter{
+		rate:     requestsPerSecond,
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+		inFlight: inFlight,
+		mutex:    new(sync.Mutex),
+	}
+}
+
+// Acquire waits for a token and a free slot, Release must be called after when no error is given.
+// An error is given when ctx is canceled while waiting.
+func (l *RateLimiter) Acquire(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			break
+		}
+		err := common.Sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+	if l.inFlight == nil {
+		return nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+func (l *RateLimiter) Release() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// reserve takes a token and gives 0 or gives the time to wait before trying again
+func (l *RateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Update pauses requests when response says that rate limit is reached,
+// it uses Retry-After header or X-RateLimit-Remaining and X-RateLimit-Reset headers from cloud controller.
+func (l *RateLimiter) Update(resp *http.Response) {
+	pauseUntil := time.Time{}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		pauseUntil = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	if pauseUntil.IsZero() && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err == nil {
+			pauseUntil = time.Unix(reset, 0)
+		}
+	}
+	if pauseUntil.IsZero() {
+		return
+	}
+	if maxPause := time.Now().Add(maxRateLimitPause); pauseUntil.After(maxPause) {
+		pauseUntil = maxPause
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if pauseUntil.After(l.pausedUntil) {
+		l.pausedUntil = pauseUntil
+	}
+}
+func parseRetryAfter(retryAfter string) time.Time {
+	if retryAfter == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return date
+	}
+	return time.Time{}
+}
+
+// RateLimitTransport sends requests through a RateLimiter
+type RateLimitTransport struct {
+	limiter   *RateLimiter
+	transport http.RoundTripper
+}
+
+func NewRateLimitTransport(limiter *RateLimiter, transport http.RoundTripper) *RateLimitTransport {
+	return &RateLimitTransport{
+		limiter:   limiter,
+		transport: transport,
+	}
+}
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := t.limiter.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	// slot is released when cloud controller answered, body can be read after
+	defer t.limiter.Release()
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	t.limiter.Update(resp)
+	return resp, nil
+}
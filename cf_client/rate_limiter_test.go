@@ -0,0 +1,73 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var _ = Describe("RateLimiter", func() {
+	It("should limit requests per second", func() {
+		limiter := NewRateLimiter(20, 0)
+		start := time.Now()
+		for i := 0; i < 30; i++ {
+			limiter.Acquire(context.Background())
+			limiter.Release()
+		}
+		// 20 requests are in the burst, 10 others need 500ms
+		Expect(time.Since(start)).To(BeNumerically(">=", 450*time.Millisecond))
+	})
+	It("should limit requests in flight", func() {
+		limiter := NewRateLimiter(0, 2)
+		var mutex sync.Mutex
+		inFlight, maxInFlight := 0, 0
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				limiter.Acquire(context.Background())
+				defer limiter.Release()
+				mutex.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mutex.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mutex.Lock()
+				inFlight--
+				mutex.Unlock()
+			}()
+		}
+		wg.Wait()
+		Expect(maxInFlight).To(Equal(2))
+	})
+	It("should pause requests when cloud controller asks to retry after", func() {
+		limiter := NewRateLimiter(0, 0)
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("Retry-After", "1")
+		limiter.Update(resp)
+		start := time.Now()
+		limiter.Acquire(context.Background())
+		limiter.Release()
+		Expect(time.Since(start)).To(BeNumerically(">=", 900*time.Millisecond))
+	})
+	It("should pause requests until reset when no request remains", func() {
+		limiter := NewRateLimiter(0, 0)
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		resp.Header.Set("X-RateLimit-Remaining", "0")
+		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(2*time.Second).Unix(), 10))
+		limiter.Update(resp)
+		start := time.Now()
+		limiter.Acquire(context.Background())
+		limiter.Release()
+		Expect(time.Since(start)).To(BeNumerically(">=", 900*time.Millisecond))
+	})
+})
@@ -0,0 +1,72 @@
+package cf_client
+
+import (
+	"fmt"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/bitsmanager"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ReadOnlyError is given when a request which could change Cloud Foundry is sent in read only mode
+type ReadOnlyError struct {
+	Method string
+	URL    string
+}
+
+func (e ReadOnlyError) Error() string {
+	return fmt.Sprintf(
+		"provider is in read only mode, %s request has been refused: set 'read_only' to false to let the provider change Cloud Foundry",
+		e.Method,
+	)
+}
+
+// ReadOnlyTransport refuses every request which is not a GET or a HEAD,
+// only uaa token requests are allowed to authenticate.
+// Cloud controller v2 gateways, v3 client and bits repository send through it as they use the provider http client.
+type ReadOnlyTransport struct {
+	transport http.RoundTripper
+}
+
+func NewReadOnlyTransport(transport http.RoundTripper) *ReadOnlyTransport {
+	return &ReadOnlyTransport{
+		transport: transport,
+	}
+}
+func (t *ReadOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		return t.transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, ReadOnlyError{
+		Method: req.Method,
+		URL:    redactURL(req.URL),
+	}
+}
+
+// ReadOnlyApplicationBitsRepository refuses uploads and copies of bits before building any request
+// to not retry them.
+type ReadOnlyApplicationBitsRepository struct {
+	bitsmanager.ApplicationBitsRepository
+}
+
+func NewReadOnlyApplicationBitsRepository(repository bitsmanager.ApplicationBitsRepository) *ReadOnlyApplicationBitsRepository {
+	return &ReadOnlyApplicationBitsRepository{
+		ApplicationBitsRepository: repository,
+	}
+}
+func (r ReadOnlyApplicationBitsRepository) UploadBits(appGUID string, zipFile io.ReadCloser, fileSize int64) error {
+	zipFile.Close()
+	return ReadOnlyError{
+		Method: http.MethodPut,
+		URL:    fmt.Sprintf("/v2/apps/%s/bits", appGUID),
+	}
+}
+func (r ReadOnlyApplicationBitsRepository) CopyBits(origAppGuid string, newAppGuid string) error {
+	return ReadOnlyError{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("/v2/apps/%s/copy_bits", newAppGuid),
+	}
+}
@@ -0,0 +1,49 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe("ReadOnlyTransport", func() {
+	var server *httptest.Server
+	var calls map[string]int
+	var client *http.Client
+	BeforeEach(func() {
+		calls = make(map[string]int)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls[r.Method+" "+r.URL.Path]++
+			w.Write([]byte(`{}`))
+		}))
+		client = &http.Client{Transport: NewReadOnlyTransport(http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("should let lookups pass", func() {
+		resp, err := client.Get(server.URL + "/v2/apps/guid")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(calls["GET /v2/apps/guid"]).To(Equal(1))
+	})
+	It("should let authentication on uaa pass", func() {
+		resp, err := client.Post(server.URL+"/oauth/token", "application/x-www-form-urlencoded", strings.NewReader("grant_type=refresh_token"))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(calls["POST /oauth/token"]).To(Equal(1))
+	})
+	It("should refuse mutating requests without sending them", func() {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/v2/apps/guid", ioutil.NopCloser(strings.NewReader("{}")))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.Do(req)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("read only mode"))
+		Expect(calls).To(BeEmpty())
+	})
+})
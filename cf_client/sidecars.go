@@ -0,0 +1,173 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
+	"code.cloudfoundry.org/cli/cf/net"
+	"encoding/json"
+	"fmt"
+)
+
+// SidecarOriginUser is the origin of sidecars created through api, others are given by buildpacks
+const SidecarOriginUser = "user"
+
+// Sidecar is a cloud controller v3 sidecar, a command run in same container than instances of process types given
+type Sidecar struct {
+	GUID         string
+	Name         string
+	Command      string
+	ProcessTypes []string
+	// MemoryInMB is taken from process memory when 0
+	MemoryInMB int64
+	Origin     string
+}
+
+type v3Sidecar struct {
+	GUID         string   `json:"guid,omitempty"`
+	Name         string   `json:"name"`
+	Command      string   `json:"command"`
+	ProcessTypes []string `json:"process_types"`
+	MemoryInMB   *int64   `json:"memory_in_mb"`
+	Origin       string   `json:"origin,omitempty"`
+}
+
+func newV3Sidecar(sidecar Sidecar) v3Sidecar {
+	v3 := v3Sidecar{
+		Name:         sidecar.Name,
+		Command:      sidecar.Command,
+		ProcessTypes: sidecar.ProcessTypes,
+	}
+	if sidecar.MemoryInMB != 0 {
+		v3.MemoryInMB = &sidecar.MemoryInMB
+	}
+	return v3
+}
+func (s v3Sidecar) ToSidecar() Sidecar {
+	sidecar := Sidecar{
+		GUID:         s.GUID,
+		Name:         s.Name,
+		Command:      s.Command,
+		ProcessTypes: s.ProcessTypes,
+		Origin:       s.Origin,
+	}
+	if s.MemoryInMB != nil {
+		sidecar.MemoryInMB = *s.MemoryInMB
+	}
+	return sidecar
+}
+
+// SidecarRepository manages cloud controller v3 sidecars of an app,
+// they are used by instances started after a change.
+type SidecarRepository interface {
+	List(appGuid string) ([]Sidecar, error)
+	Create(appGuid string, sidecar Sidecar) (Sidecar, error)
+	Update(sidecar Sidecar) error
+	Delete(sidecarGuid string) error
+}
+
+type CloudControllerSidecarRepository struct {
+	config    coreconfig.Reader
+	ccGateway net.Gateway
+}
+
+func NewCloudControllerSidecarRepository(config coreconfig.Reader, ccGateway net.Gateway) *CloudControllerSidecarRepository {
+	return &CloudControllerSidecarRepository{
+		config:    config,
+		ccGateway: ccGateway,
+	}
+}
+func (r CloudControllerSidecarRepository) List(appGuid string) ([]Sidecar, error) {
+	sidecars := make([]Sidecar, 0)
+	nextURL := fmt.Sprintf("%s/v3/apps/%s/sidecars", r.config.APIEndpoint(), appGuid)
+	for nextURL != "" {
+		page := v3Page{}
+		err := r.ccGateway.GetResource(nextURL, &page)
+		if err != nil {
+			return sidecars, err
+		}
+		for _, resource := range page.Resources {
+			sidecar := v3Sidecar{}
+			err := json.Unmarshal(resource, &sidecar)
+			if err != nil {
+				return sidecars, err
+			}
+			sidecars = append(sidecars, sidecar.ToSidecar())
+		}
+		nextURL = ""
+		if page.Pagination.Next != nil {
+			nextURL = page.Pagination.Next.Href
+		}
+	}
+	return sidecars, nil
+}
+func (r CloudControllerSidecarRepository) Create(appGuid string, sidecar Sidecar) (Sidecar, error) {
+	created := v3Sidecar{}
+	err := sendV3Request(r.config, r.ccGateway, "POST", fmt.Sprintf("/v3/apps/%s/sidecars", appGuid), newV3Sidecar(sidecar), &created)
+	if err != nil {
+		return Sidecar{}, err
+	}
+	return created.ToSidecar(), nil
+}
+func (r CloudControllerSidecarRepository) Update(sidecar Sidecar) error {
+	return sendV3Request(r.config, r.ccGateway, "PATCH", "/v3/sidecars/"+sidecar.GUID, newV3Sidecar(sidecar), &v3Sidecar{})
+}
+func (r CloudControllerSidecarRepository) Delete(sidecarGuid string) error {
+	return sendV3Request(r.config, r.ccGateway, "DELETE", "/v3/sidecars/"+sidecarGuid, nil, &v3Sidecar{})
+}
+
+// ReconcileSidecars creates, updates and deletes sidecars of app to match desired ones,
+// sidecars given by buildpacks are left untouched.
+func ReconcileSidecars(repo SidecarRepository, appGuid string, desired []Sidecar) error {
+	currentSidecars, err := repo.List(appGuid)
+	if err != nil {
+		return err
+	}
+	currentByName := make(map[string]Sidecar)
+	for _, current := range currentSidecars {
+		if current.Origin == SidecarOriginUser {
+			currentByName[current.Name] = current
+		}
+	}
+	for _, sidecar := range desired {
+		current, ok := currentByName[sidecar.Name]
+		if !ok {
+			_, err := repo.Create(appGuid, sidecar)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		delete(currentByName, sidecar.Name)
+		if current.Command == sidecar.Command &&
+			current.MemoryInMB == sidecar.MemoryInMB &&
+			sameProcessTypes(current.ProcessTypes, sidecar.ProcessTypes) {
+			continue
+		}
+		sidecar.GUID = current.GUID
+		err := repo.Update(sidecar)
+		if err != nil {
+			return err
+		}
+	}
+	for _, current := range currentByName {
+		err := repo.Delete(current.GUID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func sameProcessTypes(types1, types2 []string) bool {
+	if len(types1) != len(types2) {
+		return false
+	}
+	found := make(map[string]bool)
+	for _, processType := range types1 {
+		found[processType] = true
+	}
+	for _, processType := range types2 {
+		if !found[processType] {
+			return false
+		}
+	}
+	return true
+}
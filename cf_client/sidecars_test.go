@@ -0,0 +1,139 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("Sidecars", func() {
+	var server *httptest.Server
+	var requests []string
+	var bodies map[string]map[string]interface{}
+	var repo SidecarRepository
+	BeforeEach(func() {
+		requests = []string{}
+		bodies = map[string]map[string]interface{}{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			request := r.Method + " " + r.URL.Path
+			if r.URL.RawQuery != "" {
+				request += "?" + r.URL.RawQuery
+			}
+			requests = append(requests, request)
+			if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+				body := map[string]interface{}{}
+				json.Unmarshal(b, &body)
+				bodies[request] = body
+			}
+			switch request {
+			case "GET /v3/apps/app-guid/sidecars":
+				w.Write([]byte(`{
+					"pagination": {"next": {"href": "` + "http://" + r.Host + `/v3/apps/app-guid/sidecars?page=2"}},
+					"resources": [
+						{"guid": "reloader-guid", "name": "reloader", "command": "./reload", "process_types": ["web"], "memory_in_mb": 64, "origin": "user"},
+						{"guid": "agent-guid", "name": "agent", "command": "./agent", "process_types": ["web"], "memory_in_mb": null, "origin": "user"}
+					]
+				}`))
+			case "GET /v3/apps/app-guid/sidecars?page=2":
+				w.Write([]byte(`{
+					"pagination": {"next": null},
+					"resources": [
+						{"guid": "old-guid", "name": "old", "command": "./old", "process_types": ["worker"], "memory_in_mb": null, "origin": "user"},
+						{"guid": "apm-guid", "name": "apm", "command": "./apm", "process_types": ["web"], "memory_in_mb": null, "origin": "buildpack"}
+					]
+				}`))
+			case "POST /v3/apps/app-guid/sidecars":
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"guid": "new-guid", "name": "new", "command": "./new", "process_types": ["web"], "memory_in_mb": null, "origin": "user"}`))
+			case "PATCH /v3/sidecars/agent-guid":
+				w.Write([]byte(`{"guid": "agent-guid"}`))
+			case "DELETE /v3/sidecars/reloader-guid", "DELETE /v3/sidecars/agent-guid", "DELETE /v3/sidecars/old-guid":
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Sidecar not found"}]}`))
+			}
+		}))
+		repository, gateway := newTestGateway(server.URL)
+		repo = NewCloudControllerSidecarRepository(repository, gateway)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	Describe("List", func() {
+		It("should give sidecars of every page", func() {
+			sidecars, err := repo.List("app-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sidecars).To(Equal([]Sidecar{
+				{GUID: "reloader-guid", Name: "reloader", Command: "./reload", ProcessTypes: []string{"web"}, MemoryInMB: 64, Origin: SidecarOriginUser},
+				{GUID: "agent-guid", Name: "agent", Command: "./agent", ProcessTypes: []string{"web"}, Origin: SidecarOriginUser},
+				{GUID: "old-guid", Name: "old", Command: "./old", ProcessTypes: []string{"worker"}, Origin: SidecarOriginUser},
+				{GUID: "apm-guid", Name: "apm", Command: "./apm", ProcessTypes: []string{"web"}, Origin: "buildpack"},
+			}))
+		})
+	})
+	Describe("Create", func() {
+		It("should send memory only when it is set", func() {
+			sidecar, err := repo.Create("app-guid", Sidecar{Name: "new", Command: "./new", ProcessTypes: []string{"web"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sidecar.GUID).To(Equal("new-guid"))
+			Expect(bodies["POST /v3/apps/app-guid/sidecars"]).To(Equal(map[string]interface{}{
+				"name":          "new",
+				"command":       "./new",
+				"process_types": []interface{}{"web"},
+				"memory_in_mb":  nil,
+			}))
+		})
+	})
+	Describe("ReconcileSidecars", func() {
+		It("should create, update and delete user sidecars only when they differ", func() {
+			err := ReconcileSidecars(repo, "app-guid", []Sidecar{
+				{Name: "reloader", Command: "./reload", ProcessTypes: []string{"web"}, MemoryInMB: 64},
+				{Name: "agent", Command: "./agent", ProcessTypes: []string{"web", "worker"}},
+				{Name: "new", Command: "./new", ProcessTypes: []string{"web"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(ConsistOf(
+				"GET /v3/apps/app-guid/sidecars",
+				"GET /v3/apps/app-guid/sidecars?page=2",
+				"POST /v3/apps/app-guid/sidecars",
+				"PATCH /v3/sidecars/agent-guid",
+				"DELETE /v3/sidecars/old-guid",
+			))
+			Expect(bodies["PATCH /v3/sidecars/agent-guid"]["process_types"]).To(ConsistOf("web", "worker"))
+		})
+		It("should never touch sidecars given by buildpacks", func() {
+			err := ReconcileSidecars(repo, "app-guid", []Sidecar{
+				{Name: "apm", Command: "./my-apm", ProcessTypes: []string{"web"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(ConsistOf(
+				"GET /v3/apps/app-guid/sidecars",
+				"GET /v3/apps/app-guid/sidecars?page=2",
+				"POST /v3/apps/app-guid/sidecars",
+				"DELETE /v3/sidecars/reloader-guid",
+				"DELETE /v3/sidecars/agent-guid",
+				"DELETE /v3/sidecars/old-guid",
+			))
+			Expect(bodies["POST /v3/apps/app-guid/sidecars"]["name"]).To(Equal("apm"))
+		})
+		It("should only list sidecars when they already match", func() {
+			err := ReconcileSidecars(repo, "app-guid", []Sidecar{
+				{Name: "reloader", Command: "./reload", ProcessTypes: []string{"web"}, MemoryInMB: 64},
+				{Name: "agent", Command: "./agent", ProcessTypes: []string{"web"}},
+				{Name: "old", Command: "./old", ProcessTypes: []string{"worker"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{
+				"GET /v3/apps/app-guid/sidecars",
+				"GET /v3/apps/app-guid/sidecars?page=2",
+			}))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
 	"code.cloudfoundry.org/cli/cf/models"
 	"github.com/blang/semver"
+	"log"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +39,12 @@ type TerraformRepository struct {
 	skipInsecureSSL          bool
 	space                    models.SpaceFields
 	org                      models.OrganizationFields
+	cliConfig                *CliConfig
+	dialTimeout              time.Duration
+	accessTokenFile          string
+	refreshTokenFile         string
+	accessTokenFromFile      string
+	refreshTokenFromFile     string
 	mutex                    *sync.RWMutex
 }
 
@@ -85,8 +92,11 @@ func (c *TerraformRepository) BinaryVersion() string {
 	return c.appVersion
 }
 
-func (c *TerraformRepository) DialTimeout() time.Duration {
-	return 30 * time.Second
+func (c *TerraformRepository) DialTimeout() (dialTimeout time.Duration) {
+	c.read(func() {
+		dialTimeout = c.dialTimeout
+	})
+	return
 }
 
 func (c *TerraformRepository) SetUAAEndpoint(uaaEndpoint string) {
@@ -99,8 +109,11 @@ func (c *TerraformRepository) UAADisableKeepAlives() bool {
 	return false
 }
 
-func (c *TerraformRepository) UAAGrantType() string {
-	return ""
+func (c *TerraformRepository) UAAGrantType() (grantType string) {
+	c.read(func() {
+		grantType = c.grantType
+	})
+	return
 }
 
 func (c *TerraformRepository) SetUAAGrantType(grantType string) {
@@ -155,7 +168,13 @@ func (c *TerraformRepository) SSHOAuthClient() (clientID string) {
 	return
 }
 
+// RefreshToken is asked when a refresh is triggered,
+// refresh token file is read again to use the one rotated since, if any.
 func (c *TerraformRepository) RefreshToken() (refreshToken string) {
+	err := c.reloadRefreshTokenFile()
+	if err != nil {
+		log.Printf("[WARN] refresh token file can't be read, using last refresh token: %s", err.Error())
+	}
 	c.read(func() {
 		refreshToken = c.refreshToken
 	})
@@ -206,14 +225,14 @@ func (c *TerraformRepository) IsLoggedIn() (loggedIn bool) {
 
 func (c *TerraformRepository) HasOrganization() (hasOrg bool) {
 	c.read(func() {
-		hasOrg = false
+		hasOrg = c.org.GUID != ""
 	})
 	return
 }
 
 func (c *TerraformRepository) HasSpace() (hasSpace bool) {
 	c.read(func() {
-		hasSpace = false
+		hasSpace = c.space.GUID != ""
 	})
 	return
 }
@@ -373,6 +392,7 @@ func (c *TerraformRepository) SetAccessToken(token string) {
 	c.write(func() {
 		c.accessToken = token
 	})
+	c.saveTokensToCli()
 }
 
 func (c *TerraformRepository) SetSSHOAuthClient(clientID string) {
@@ -385,6 +405,91 @@ func (c *TerraformRepository) SetRefreshToken(token string) {
 	c.write(func() {
 		c.refreshToken = token
 	})
+	c.saveTokensToCli()
+}
+
+func (c *TerraformRepository) SetCliConfig(cliConfig *CliConfig) {
+	c.write(func() {
+		c.cliConfig = cliConfig
+	})
+}
+
+func (c *TerraformRepository) SetDialTimeout(dialTimeout time.Duration) {
+	c.write(func() {
+		c.dialTimeout = dialTimeout
+	})
+}
+
+// SetTokenFiles sets files where tokens are read again when a refresh is triggered
+func (c *TerraformRepository) SetTokenFiles(accessTokenFile, refreshTokenFile string) error {
+	c.write(func() {
+		c.accessTokenFile = accessTokenFile
+		c.refreshTokenFile = refreshTokenFile
+	})
+	_, err := c.ReloadTokenFiles()
+	return err
+}
+
+// ReloadTokenFiles reads again token files,
+// it gives true when access token in file has changed since the last time it was read
+func (c *TerraformRepository) ReloadTokenFiles() (changed bool, err error) {
+	var accessTokenFile string
+	c.read(func() {
+		accessTokenFile = c.accessTokenFile
+	})
+	err = c.reloadRefreshTokenFile()
+	if err != nil || accessTokenFile == "" {
+		return false, err
+	}
+	accessToken, err := readTokenFile(accessTokenFile)
+	if err != nil {
+		return false, err
+	}
+	accessToken = bearerToken(accessToken)
+	c.write(func() {
+		if accessToken == "" || accessToken == c.accessTokenFromFile {
+			return
+		}
+		changed = true
+		c.accessTokenFromFile = accessToken
+		c.accessToken = accessToken
+	})
+	return changed, nil
+}
+func (c *TerraformRepository) reloadRefreshTokenFile() error {
+	var refreshTokenFile string
+	c.read(func() {
+		refreshTokenFile = c.refreshTokenFile
+	})
+	refreshToken, err := readTokenFile(refreshTokenFile)
+	if err != nil || refreshToken == "" {
+		return err
+	}
+	c.write(func() {
+		if refreshToken == c.refreshTokenFromFile {
+			return
+		}
+		c.refreshTokenFromFile = refreshToken
+		c.refreshToken = refreshToken
+	})
+	return nil
+}
+
+func (c *TerraformRepository) saveTokensToCli() {
+	var cliConfig *CliConfig
+	var accessToken, refreshToken string
+	c.read(func() {
+		cliConfig = c.cliConfig
+		accessToken = c.accessToken
+		refreshToken = c.refreshToken
+	})
+	if cliConfig == nil {
+		return
+	}
+	err := cliConfig.SaveTokens(accessToken, refreshToken)
+	if err != nil {
+		log.Printf("[WARN] refreshed tokens can't be written back to cf cli config file %s: %s", cliConfig.Path(), err.Error())
+	}
 }
 
 func (c *TerraformRepository) SetOrganizationFields(org models.OrganizationFields) {
@@ -492,5 +597,6 @@ func NewTerraformRepository(appName, appVersion string, skipInsecureSSL bool) *T
 		appName:         appName,
 		appVersion:      appVersion,
 		skipInsecureSSL: skipInsecureSSL,
+		dialTimeout:     30 * time.Second,
 	}
 }
@@ -0,0 +1,25 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"code.cloudfoundry.org/cli/cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TerraformRepository", func() {
+	var repository *TerraformRepository
+	BeforeEach(func() {
+		repository = NewTerraformRepository("app", "1.0.0", false)
+	})
+	It("should have an organization and a space only when they were set", func() {
+		Expect(repository.HasOrganization()).To(BeFalse())
+		Expect(repository.HasSpace()).To(BeFalse())
+
+		repository.SetOrganizationFields(models.OrganizationFields{GUID: "org-guid", Name: "my-org"})
+		repository.SetSpaceFields(models.SpaceFields{GUID: "space-guid", Name: "my-space"})
+		Expect(repository.HasOrganization()).To(BeTrue())
+		Expect(repository.HasSpace()).To(BeTrue())
+	})
+})
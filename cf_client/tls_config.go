@@ -0,0 +1,63 @@
+package cf_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/mitchellh/go-homedir"
+	"io/ioutil"
+	"strings"
+)
+
+// NewTLSConfig creates the tls config shared by every transport of the provider
+// from the ca bundle and client certificate given in config.
+func NewTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.SkipInsecureSSL,
+	}
+	if config.CACert != "" {
+		caCert, err := readPEM(config.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("Error when reading 'ca_cert': %s", err.Error())
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("Error when reading 'ca_cert': no valid pem certificate found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if (config.ClientCert == "") != (config.ClientKey == "") {
+		return nil, fmt.Errorf("You must provide both 'client_cert' and 'client_key' to use mutual tls.")
+	}
+	if config.ClientCert != "" {
+		clientCert, err := readPEM(config.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("Error when reading 'client_cert': %s", err.Error())
+		}
+		clientKey, err := readPEM(config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error when reading 'client_key': %s", err.Error())
+		}
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error when loading client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// readPEM gives the pem content directly given or read it from the given path
+func readPEM(pemOrPath string) ([]byte, error) {
+	if strings.Contains(pemOrPath, "-----BEGIN") {
+		return []byte(pemOrPath), nil
+	}
+	path, err := homedir.Expand(strings.TrimSpace(pemOrPath))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
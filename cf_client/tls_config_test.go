@@ -0,0 +1,61 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"encoding/pem"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("NewTLSConfig", func() {
+	var server *httptest.Server
+	var caCert string
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		caCert = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}))
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	get := func(config Config) error {
+		tlsConfig, err := NewTLSConfig(config)
+		Expect(err).ToNot(HaveOccurred())
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		_, err = client.Get(server.URL)
+		return err
+	}
+	It("should reject unknown certificate authority", func() {
+		Expect(get(Config{})).ToNot(Succeed())
+	})
+	It("should trust ca given as pem", func() {
+		Expect(get(Config{CACert: caCert})).To(Succeed())
+	})
+	It("should trust ca given as path", func() {
+		tmpDir, err := ioutil.TempDir("", "ca-cert")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		caPath := filepath.Join(tmpDir, "ca.pem")
+		Expect(ioutil.WriteFile(caPath, []byte(caCert), 0600)).To(Succeed())
+
+		Expect(get(Config{CACert: caPath})).To(Succeed())
+	})
+	It("should return an error when ca is not a valid pem", func() {
+		_, err := NewTLSConfig(Config{CACert: "-----BEGIN CERTIFICATE-----\nnotacert\n-----END CERTIFICATE-----"})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should return an error when client key is missing", func() {
+		_, err := NewTLSConfig(Config{ClientCert: caCert})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,75 @@
+package cf_client
+
+import (
+	"code.cloudfoundry.org/cli/api/uaa"
+	"code.cloudfoundry.org/cli/cf/configuration/coreconfig"
+	"github.com/mitchellh/go-homedir"
+	"io/ioutil"
+	"strings"
+)
+
+// TokenFileReloader is a token cache able to read again tokens rendered in files
+type TokenFileReloader interface {
+	// ReloadTokenFiles gives true when access token in file has changed since the last time it was read
+	ReloadTokenFiles() (bool, error)
+}
+
+// readTokenFile reads a token written in a file, an empty path gives an empty token
+func readTokenFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// bearerToken gives token in the form used in authorization header
+func bearerToken(token string) string {
+	if token == "" || strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		return token
+	}
+	return "bearer " + token
+}
+
+// TokenFileUAAClient uses access token from file when it was rotated instead of refreshing it through uaa
+type TokenFileUAAClient struct {
+	uaaClient *uaa.Client
+	config    coreconfig.Reader
+}
+
+func NewTokenFileUAAClient(uaaClient *uaa.Client, config coreconfig.Reader) *TokenFileUAAClient {
+	return &TokenFileUAAClient{
+		uaaClient: uaaClient,
+		config:    config,
+	}
+}
+func (c *TokenFileUAAClient) RefreshAccessToken(refreshToken string) (uaa.RefreshedTokens, error) {
+	changed, err := reloadTokenFiles(c.config)
+	if err != nil {
+		return uaa.RefreshedTokens{}, err
+	}
+	if !changed {
+		return c.uaaClient.RefreshAccessToken(refreshToken)
+	}
+	return uaa.RefreshedTokens{
+		AccessToken:  strings.TrimSpace(c.config.AccessToken()[len("bearer "):]),
+		RefreshToken: c.config.RefreshToken(),
+		Type:         "bearer",
+	}, nil
+}
+
+// reloadTokenFiles reads again token files if config supports it
+func reloadTokenFiles(config coreconfig.Reader) (bool, error) {
+	reloader, ok := config.(TokenFileReloader)
+	if !ok {
+		return false, nil
+	}
+	return reloader.ReloadTokenFiles()
+}
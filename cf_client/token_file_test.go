@@ -0,0 +1,78 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("TokenFile", func() {
+	var tmpDir string
+	var accessTokenFile string
+	var refreshTokenFile string
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cf-tokens")
+		Expect(err).ToNot(HaveOccurred())
+		accessTokenFile = filepath.Join(tmpDir, "token")
+		refreshTokenFile = filepath.Join(tmpDir, "refresh-token")
+		Expect(ioutil.WriteFile(accessTokenFile, []byte("access\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(refreshTokenFile, []byte("refresh\n"), 0600)).To(Succeed())
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+	Describe("Config", func() {
+		It("should read tokens from files over given tokens", func() {
+			config := Config{
+				UserAccessToken:  "bearer other",
+				AccessTokenFile:  accessTokenFile,
+				RefreshTokenFile: refreshTokenFile,
+			}
+			Expect(config.LoadTokenFiles()).To(Succeed())
+			Expect(config.UserAccessToken).To(Equal("bearer access"))
+			Expect(config.UserRefreshToken).To(Equal("refresh"))
+		})
+		It("should return an error when a token file doesn't exist", func() {
+			config := Config{AccessTokenFile: filepath.Join(tmpDir, "notfound")}
+			Expect(config.LoadTokenFiles()).ToNot(Succeed())
+		})
+	})
+	Describe("TerraformRepository", func() {
+		var repository *TerraformRepository
+		BeforeEach(func() {
+			repository = NewTerraformRepository("app", "1.0.0", false)
+			Expect(repository.SetTokenFiles(accessTokenFile, refreshTokenFile)).To(Succeed())
+		})
+		It("should use access token from file only when it has been rotated", func() {
+			Expect(repository.AccessToken()).To(Equal("bearer access"))
+
+			changed, err := repository.ReloadTokenFiles()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+
+			Expect(ioutil.WriteFile(accessTokenFile, []byte("rotated"), 0600)).To(Succeed())
+			changed, err = repository.ReloadTokenFiles()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(repository.AccessToken()).To(Equal("bearer rotated"))
+		})
+		It("should keep a token refreshed through uaa until file is rotated", func() {
+			repository.SetAccessToken("bearer from-uaa")
+			repository.SetRefreshToken("refresh-from-uaa")
+			changed, err := repository.ReloadTokenFiles()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(repository.AccessToken()).To(Equal("bearer from-uaa"))
+			Expect(repository.RefreshToken()).To(Equal("refresh-from-uaa"))
+		})
+		It("should read again refresh token file when refresh token is asked", func() {
+			Expect(ioutil.WriteFile(refreshTokenFile, []byte("rotated-refresh"), 0600)).To(Succeed())
+			Expect(repository.RefreshToken()).To(Equal("rotated-refresh"))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+package cf_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/mitchellh/go-homedir"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redactedValue    = "[REDACTED]"
+	maxTracedBodyLen = 64 * 1024
+)
+
+// sensitiveKeys are json or form keys which are always masked in traces,
+// keys containing password, secret or token are also masked.
+var sensitiveKeys = map[string]bool{
+	"authorization":    true,
+	"cookie":           true,
+	"set-cookie":       true,
+	"credentials":      true,
+	"parameters":       true,
+	"environment_json": true,
+	"auth_username":    true,
+	"private_key":      true,
+	"passphrase":       true,
+}
+
+// RequestTrace is a json line written in trace file for each http exchange
+type RequestTrace struct {
+	Time           time.Time         `json:"time"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Status         int               `json:"status,omitempty"`
+	DurationMs     int64             `json:"duration_ms"`
+	VcapRequestID  string            `json:"x_vcap_request_id,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    interface{}       `json:"request_body,omitempty"`
+	ResponseBody   interface{}       `json:"response_body,omitempty"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// RequestTracer writes redacted http exchanges as json lines in a file
+type RequestTracer struct {
+	writer io.Writer
+	mutex  *sync.Mutex
+}
+
+func NewRequestTracer(path string) (*RequestTracer, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewRequestTracerWithWriter(f), nil
+}
+func NewRequestTracerWithWriter(writer io.Writer) *RequestTracer {
+	return &RequestTracer{
+		writer: writer,
+		mutex:  new(sync.Mutex),
+	}
+}
+
+// Close closes trace file, it does nothing when tracer doesn't write in a file
+func (t *RequestTracer) Close() error {
+	closer, ok := t.writer.(io.Closer)
+	if !ok {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return closer.Close()
+}
+func (t *RequestTracer) Trace(trace RequestTrace) {
+	b, err := json.Marshal(trace)
+	if err != nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.writer.Write(append(b, '\n'))
+}
+
+// TraceTransport sends each http exchange made through it to a RequestTracer
+type TraceTransport struct {
+	tracer    *RequestTracer
+	transport http.RoundTripper
+}
+
+func NewTraceTransport(tracer *RequestTracer, transport http.RoundTripper) *TraceTransport {
+	return &TraceTransport{
+		tracer:    tracer,
+		transport: transport,
+	}
+}
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := RequestTrace{
+		Time:           time.Now(),
+		Method:         req.Method,
+		URL:            redactURL(req.URL),
+		VcapRequestID:  req.Header.Get("X-Vcap-Request-Id"),
+		RequestHeaders: redactHeaders(req.Header),
+		RequestBody:    traceRequestBody(req),
+	}
+	resp, err := t.transport.RoundTrip(req)
+	trace.DurationMs = int64(time.Since(trace.Time) / time.Millisecond)
+	if err != nil {
+		trace.Error = err.Error()
+		t.tracer.Trace(trace)
+		return resp, err
+	}
+	trace.Status = resp.StatusCode
+	if requestID := resp.Header.Get("X-Vcap-Request-Id"); requestID != "" {
+		trace.VcapRequestID = requestID
+	}
+	trace.ResponseBody = traceResponseBody(resp)
+	t.tracer.Trace(trace)
+	return resp, nil
+}
+func traceRequestBody(req *http.Request) interface{} {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil || req.ContentLength > maxTracedBodyLen {
+		return "[stream]"
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return redactBody(req.Header.Get("Content-Type"), b)
+}
+func traceResponseBody(resp *http.Response) interface{} {
+	contentType := resp.Header.Get("Content-Type")
+	if resp.Body == nil || !isTextContent(contentType) || resp.ContentLength > maxTracedBodyLen {
+		return nil
+	}
+	b, complete, err := peekResponseBody(resp)
+	if err != nil {
+		return nil
+	}
+	if !complete {
+		return "[truncated]"
+	}
+	return redactBody(contentType, b)
+}
+
+// peekResponseBody reads at most maxTracedBodyLen bytes of response body, even when its length is unknown (chunked response),
+// body is given back to response untouched. complete is false when body is longer than what was read.
+func peekResponseBody(resp *http.Response) (b []byte, complete bool, err error) {
+	b, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxTracedBodyLen+1))
+	resp.Body = &peekedBody{
+		Reader: io.MultiReader(bytes.NewReader(b), resp.Body),
+		body:   resp.Body,
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, len(b) <= maxTracedBodyLen, nil
+}
+
+// peekedBody is a response body whose beginning was already read
+type peekedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *peekedBody) Close() error {
+	return b.body.Close()
+}
+func isTextContent(contentType string) bool {
+	return strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "x-www-form-urlencoded") ||
+		strings.HasPrefix(contentType, "text/")
+}
+func redactBody(contentType string, b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	if strings.Contains(contentType, "x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(b))
+		if err == nil {
+			return redactValues(values).Encode()
+		}
+	}
+	var content interface{}
+	if json.Unmarshal(b, &content) == nil {
+		return redactJSON(content)
+	}
+	if len(b) > maxTracedBodyLen {
+		return "[truncated]"
+	}
+	return string(b)
+}
+func redactJSON(content interface{}) interface{} {
+	switch c := content.(type) {
+	case map[string]interface{}:
+		for key, value := range c {
+			if isSensitiveKey(key) {
+				c[key] = redactedValue
+				continue
+			}
+			c[key] = redactJSON(value)
+		}
+	case []interface{}:
+		for i, value := range c {
+			c[i] = redactJSON(value)
+		}
+	}
+	return content
+}
+func redactValues(values url.Values) url.Values {
+	for key := range values {
+		if isSensitiveKey(key) {
+			values.Set(key, redactedValue)
+		}
+	}
+	return values
+}
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for key, values := range header {
+		if isSensitiveKey(key) {
+			headers[key] = redactedValue
+			continue
+		}
+		headers[key] = strings.Join(values, ",")
+	}
+	return headers
+}
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redacted.User.Username())
+	}
+	redacted.RawQuery = redactValues(redacted.Query()).Encode()
+	return redacted.String()
+}
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return sensitiveKeys[key] ||
+		strings.Contains(key, "password") ||
+		strings.Contains(key, "secret") ||
+		strings.Contains(key, "token")
+}
@@ -0,0 +1,112 @@
+package cf_client_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+
+	"bytes"
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var _ = Describe("TraceTransport", func() {
+	var server *httptest.Server
+	var buf *bytes.Buffer
+	var client *http.Client
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Vcap-Request-Id", "request-id")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"name":"my-broker","credentials":{"password":"secret"},"access_token":"token"}`))
+		}))
+		buf = new(bytes.Buffer)
+		client = &http.Client{Transport: NewTraceTransport(NewRequestTracerWithWriter(buf), http.DefaultTransport)}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	readTrace := func() map[string]interface{} {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(1))
+		var trace map[string]interface{}
+		Expect(json.Unmarshal([]byte(lines[0]), &trace)).To(Succeed())
+		return trace
+	}
+	It("should write a redacted json line for a json exchange", func() {
+		req, _ := http.NewRequest("POST", server.URL+"/v2/service_brokers", strings.NewReader(`{"name":"my-broker","auth_username":"admin","auth_password":"secret"}`))
+		req.Header.Set("Authorization", "bearer token")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		b, _ := ioutil.ReadAll(resp.Body)
+		Expect(string(b)).To(ContainSubstring(`"password":"secret"`))
+
+		trace := readTrace()
+		Expect(trace["method"]).To(Equal("POST"))
+		Expect(trace["url"]).To(Equal(server.URL + "/v2/service_brokers"))
+		Expect(trace["status"]).To(BeNumerically("==", http.StatusCreated))
+		Expect(trace["x_vcap_request_id"]).To(Equal("request-id"))
+		Expect(trace["request_headers"]).To(HaveKeyWithValue("Authorization", "[REDACTED]"))
+		Expect(trace["request_body"]).To(Equal(map[string]interface{}{
+			"name":          "my-broker",
+			"auth_username": "[REDACTED]",
+			"auth_password": "[REDACTED]",
+		}))
+		Expect(trace["response_body"]).To(Equal(map[string]interface{}{
+			"name":         "my-broker",
+			"credentials":  "[REDACTED]",
+			"access_token": "[REDACTED]",
+		}))
+	})
+	It("should redact form values sent to uaa", func() {
+		form := url.Values{"grant_type": {"password"}, "username": {"admin"}, "password": {"secret"}}
+		_, err := client.PostForm(server.URL+"/oauth/token", form)
+		Expect(err).ToNot(HaveOccurred())
+
+		body := readTrace()["request_body"].(string)
+		Expect(body).ToNot(ContainSubstring("secret"))
+		Expect(body).To(ContainSubstring("username=admin"))
+	})
+	It("should not read streamed bodies", func() {
+		req, _ := http.NewRequest("PUT", server.URL+"/v2/apps/guid/bits", ioutil.NopCloser(strings.NewReader("zip content")))
+		_, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(readTrace()["request_body"]).To(Equal("[stream]"))
+	})
+	It("should only keep beginning of a chunked response body and give whole body back", func() {
+		large := `{"name":"` + strings.Repeat("a", 128*1024) + `"}`
+		chunked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(large[:10]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(large[10:]))
+		}))
+		defer chunked.Close()
+		resp, err := client.Get(chunked.URL + "/v2/apps")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.ContentLength).To(BeNumerically("==", -1))
+		b, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(string(b)).To(Equal(large))
+		Expect(readTrace()["response_body"]).To(Equal("[truncated]"))
+	})
+	It("should close trace file", func() {
+		f, err := ioutil.TempFile("", "trace")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(f.Name())
+		f.Close()
+		tracer, err := NewRequestTracer(f.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tracer.Close()).To(Succeed())
+		Expect(tracer.Close()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy describes how and when a failed call to Cloud Foundry must be retried
+type RetryPolicy struct {
+	MaxAttempts          int
+	InitialBackoff       time.Duration
+	MaxBackoff           time.Duration
+	Jitter               bool
+	RetryableStatusCodes []int
+	DialTimeout          time.Duration
+	RequestTimeout       time.Duration
+	JobTimeout           time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Jitter:         true,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		DialTimeout:    10 * time.Second,
+		RequestTimeout: 2 * time.Minute,
+		JobTimeout:     30 * time.Minute,
+	}
+}
+func (p RetryPolicy) IsRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// JobTimeoutMinutes gives job timeout in minutes for cf cli config, rounded up to keep at least the given timeout
+func (p RetryPolicy) JobTimeoutMinutes() uint {
+	minutes := uint((p.JobTimeout + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
+
+// Backoff gives the time to wait before the next attempt, attempt starts at 0
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 0; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if !p.Jitter || backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)))
+}
+
+// Retry calls retryFunc until it succeeds, it says that error can't be retried, max attempts is reached or ctx is canceled
+func (p RetryPolicy) Retry(ctx context.Context, retryFunc func() (bool, error)) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		retryable, err = retryFunc()
+		if err == nil || !retryable || attempt+1 >= p.MaxAttempts {
+			return err
+		}
+		if sleepErr := Sleep(ctx, p.Backoff(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
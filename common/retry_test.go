@@ -0,0 +1,87 @@
+package common_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+
+	"context"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	var policy RetryPolicy
+	BeforeEach(func() {
+		policy = DefaultRetryPolicy()
+		policy.InitialBackoff = time.Millisecond
+		policy.MaxBackoff = 4 * time.Millisecond
+	})
+	Describe("Backoff", func() {
+		It("should double backoff until max backoff without jitter", func() {
+			policy.Jitter = false
+			Expect(policy.Backoff(0)).Should(Equal(time.Millisecond))
+			Expect(policy.Backoff(1)).Should(Equal(2 * time.Millisecond))
+			Expect(policy.Backoff(2)).Should(Equal(4 * time.Millisecond))
+			Expect(policy.Backoff(10)).Should(Equal(4 * time.Millisecond))
+		})
+		It("should keep at least half of the backoff with jitter", func() {
+			backoff := policy.Backoff(2)
+			Expect(backoff).Should(BeNumerically(">=", 2*time.Millisecond))
+			Expect(backoff).Should(BeNumerically("<=", 4*time.Millisecond))
+		})
+	})
+	Describe("JobTimeoutMinutes", func() {
+		It("should round job timeout up to a minute", func() {
+			Expect(RetryPolicy{JobTimeout: 90 * time.Second}.JobTimeoutMinutes()).To(Equal(uint(2)))
+			Expect(RetryPolicy{JobTimeout: 30 * time.Minute}.JobTimeoutMinutes()).To(Equal(uint(30)))
+		})
+		It("should give at least a minute", func() {
+			Expect(RetryPolicy{JobTimeout: 10 * time.Second}.JobTimeoutMinutes()).To(Equal(uint(1)))
+			Expect(RetryPolicy{}.JobTimeoutMinutes()).To(Equal(uint(1)))
+		})
+	})
+	Describe("Retry", func() {
+		It("should stop after max attempts", func() {
+			attempts := 0
+			err := policy.Retry(context.Background(), func() (bool, error) {
+				attempts++
+				return true, errors.New("failure")
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(attempts).Should(Equal(policy.MaxAttempts))
+		})
+		It("should stop on non retryable error", func() {
+			attempts := 0
+			err := policy.Retry(context.Background(), func() (bool, error) {
+				attempts++
+				return false, errors.New("failure")
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(attempts).Should(Equal(1))
+		})
+		It("should stop when it succeeds", func() {
+			attempts := 0
+			err := policy.Retry(context.Background(), func() (bool, error) {
+				attempts++
+				if attempts < 2 {
+					return true, errors.New("failure")
+				}
+				return true, nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(attempts).Should(Equal(2))
+		})
+		It("should stop when context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			attempts := 0
+			err := policy.Retry(ctx, func() (bool, error) {
+				attempts++
+				cancel()
+				return true, errors.New("failure")
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(attempts).Should(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SmokeTest describes an http probe which must pass before an app receives production traffic
+type SmokeTest struct {
+	Path           string
+	ExpectedStatus int
+	// BodyRegex must match response body when it is set
+	BodyRegex *regexp.Regexp
+	// Retries is the number of probes done after a failed one
+	Retries  int
+	Interval time.Duration
+}
+
+// Probe calls path on baseURL until it answers as expected, it gives error of last probe when every retry failed
+func (s SmokeTest) Probe(ctx context.Context, client *http.Client, baseURL string) error {
+	url := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(s.Path, "/")
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		err = s.probeOnce(ctx, client, url)
+		if err == nil || attempts > s.Retries {
+			break
+		}
+		if sleepErr := Sleep(ctx, s.Interval); sleepErr != nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Smoke test on %s failed after %d attempt(s): %s", url, attempts, err.Error())
+	}
+	return nil
+}
+func (s SmokeTest) probeOnce(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != s.ExpectedStatus {
+		return fmt.Errorf("status code %d received instead of %d", resp.StatusCode, s.ExpectedStatus)
+	}
+	if s.BodyRegex == nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if !s.BodyRegex.Match(body) {
+		return fmt.Errorf("body doesn't match '%s'", s.BodyRegex.String())
+	}
+	return nil
+}
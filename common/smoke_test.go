@@ -0,0 +1,78 @@
+package common_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
+
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+)
+
+var _ = Describe("SmokeTest", func() {
+	var server *httptest.Server
+	var calls int
+	var healthyAfter int
+	var smokeTest SmokeTest
+	BeforeEach(func() {
+		calls = 0
+		healthyAfter = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.URL.Path != "/health" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if calls <= healthyAfter {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"status": "UP"}`))
+		}))
+		smokeTest = SmokeTest{
+			Path:           "/health",
+			ExpectedStatus: http.StatusOK,
+			BodyRegex:      regexp.MustCompile(`"status":\s*"UP"`),
+			Retries:        2,
+			Interval:       time.Millisecond,
+		}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("should pass when app answers as expected", func() {
+		Expect(smokeTest.Probe(context.Background(), http.DefaultClient, server.URL)).To(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+	It("should retry until app answers as expected", func() {
+		healthyAfter = 2
+		Expect(smokeTest.Probe(context.Background(), http.DefaultClient, server.URL+"/")).To(Succeed())
+		Expect(calls).To(Equal(3))
+	})
+	It("should fail when status is not the one expected after every retry", func() {
+		smokeTest.Path = "unknown"
+		err := smokeTest.Probe(context.Background(), http.DefaultClient, server.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed after 3 attempt(s): status code 404 received instead of 200"))
+		Expect(calls).To(Equal(3))
+	})
+	It("should fail when body doesn't match", func() {
+		smokeTest.BodyRegex = regexp.MustCompile("DOWN")
+		smokeTest.Retries = 0
+		err := smokeTest.Probe(context.Background(), http.DefaultClient, server.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("body doesn't match 'DOWN'"))
+		Expect(calls).To(Equal(1))
+	})
+	It("should stop retrying when context is canceled", func() {
+		healthyAfter = 10
+		smokeTest.Interval = time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(smokeTest.Probe(ctx, http.DefaultClient, server.URL)).ToNot(Succeed())
+		Expect(calls).To(Equal(0))
+	})
+})
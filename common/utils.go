@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"strings"
@@ -38,8 +39,19 @@ func VarToIntPointer(data int) *int {
 	}
 	return &data
 }
-func Polling(pollingFunc func() (bool, error), waitTime time.Duration) error {
 
+// Sleep waits for duration or gives ctx error when it is canceled before
+func Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+func Polling(ctx context.Context, pollingFunc func() (bool, error), waitTime time.Duration) error {
 	for {
 		finished, err := pollingFunc()
 		if err != nil {
@@ -48,11 +60,13 @@ func Polling(pollingFunc func() (bool, error), waitTime time.Duration) error {
 		if finished {
 			return nil
 		}
-		time.Sleep(waitTime)
+		err = Sleep(ctx, waitTime)
+		if err != nil {
+			return err
+		}
 	}
-	return nil
 }
-func PollingWithTimeout(pollingFunc func() (bool, error), waitTime time.Duration, timeout time.Duration) error {
+func PollingWithTimeout(ctx context.Context, pollingFunc func() (bool, error), waitTime time.Duration, timeout time.Duration) error {
 	stagingStartTime := time.Now()
 	for {
 		if time.Since(stagingStartTime) > timeout {
@@ -65,7 +79,9 @@ func PollingWithTimeout(pollingFunc func() (bool, error), waitTime time.Duration
 		if finished {
 			return nil
 		}
-		time.Sleep(waitTime)
+		err = Sleep(ctx, waitTime)
+		if err != nil {
+			return err
+		}
 	}
-	return nil
 }
@@ -3,8 +3,10 @@ package common_test
 import (
 	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 
+	"context"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"time"
 )
 
 var _ = Describe("Utils", func() {
@@ -23,4 +25,17 @@ var _ = Describe("Utils", func() {
 			})
 		})
 	})
+	Describe("Polling", func() {
+		It("should stop when context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			calls := 0
+			err := Polling(ctx, func() (bool, error) {
+				calls++
+				cancel()
+				return false, nil
+			}, time.Hour)
+			Expect(err).Should(Equal(context.Canceled))
+			Expect(calls).Should(Equal(1))
+		})
+	})
 })
@@ -1,37 +1,66 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/plugin"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/resources"
 	"strings"
+	"time"
 )
 
 func Provider() terraform.ResourceProvider {
 
 	// The actual provider
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_endpoint": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CF_API", ""),
-				Description: "Your Cloud Foundry api url.",
+				Description: "Your Cloud Foundry api url. (Optional if it can be found in 'cf_config_path')",
+			},
+			"cf_config_path": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CONFIG_PATH", ""),
+				Description: "Path to a cf cli config.json to use target and session from an existing 'cf login' (default to ~/.cf/config.json).",
 			},
 			"username": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CF_USERNAME", ""),
-				Description: "The username of an admin user. (Optional if you use an access token)",
+				Description: "The username of an admin user, or of any user when non_admin is true. (Optional if you use an access token)",
 			},
 			"password": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CF_PASSWORD", ""),
-				Description: "The password of an admin user. (Optional if you use an access token)",
+				Description: "The password of an admin user, or of any user when non_admin is true. (Optional if you use an access token)",
+			},
+			"client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CLIENT_ID", ""),
+				Description: "The UAA client id used to authenticate with a client_credentials grant. (Optional if you use 'username' and 'password' or an access token)",
+			},
+			"client_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CLIENT_SECRET", ""),
+				Description: "The UAA client secret associated to 'client_id'.",
+			},
+			"origin": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_ORIGIN", ""),
+				Description: "The identity provider origin (e.g.: ldap) to use when login with 'username' and 'password'.",
 			},
 			"enc_private_key": &schema.Schema{
 				Type:        schema.TypeString,
@@ -57,21 +86,227 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("CF_TOKEN", ""),
 				Description: "The OAuth token used to connect to a Cloud Foundry. (Optional if you use 'username' and 'password')",
 			},
+			"access_token_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_TOKEN_FILE", ""),
+				Description: "Path to a file containing the OAuth token, it is read again when token is refreshed. (Takes precedence over 'user_access_token')",
+			},
+			"refresh_token_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_REFRESH_TOKEN_FILE", ""),
+				Description: "Path to a file containing the OAuth refresh token, it is read again when token is refreshed. (Takes precedence over 'user_refresh_token')",
+			},
 			"verbose": &schema.Schema{
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
 				Description: "Set to true to see request sent to Cloud Foundry.",
 			},
+			"trace_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_TRACE_FILE", ""),
+				Description: "Path to a file where each request sent to Cloud Foundry is written as a json line, secrets are redacted.",
+			},
+			"journal_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_JOURNAL_FILE", ""),
+				Description: "Path to a file where each change made on Cloud Foundry is appended as a json line.",
+			},
 			"skip_ssl_validation": &schema.Schema{
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
 				Description: "Set to true to skip verification of the API endpoint. Not recommended!",
 			},
+			"http_proxy": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"HTTP_PROXY", "http_proxy"}, ""),
+				Description: "Proxy used for http requests made by the provider.",
+			},
+			"https_proxy": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"HTTPS_PROXY", "https_proxy"}, ""),
+				Description: "Proxy used for https and websocket requests made by the provider.",
+			},
+			"no_proxy": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NO_PROXY", "no_proxy"}, ""),
+				Description: "Comma separated list of hosts, domains or cidrs which must not go through proxies.",
+			},
+			"ca_cert": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CA_CERT", ""),
+				Description: "A pem ca bundle (content or path to a file) used to verify certificates of Cloud Foundry and of every endpoint contacted by the provider.",
+			},
+			"client_cert": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CLIENT_CERT", ""),
+				Description: "A pem client certificate (content or path to a file) to use mutual tls. Need a key with 'client_key'.",
+			},
+			"client_key": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_CLIENT_KEY", ""),
+				Description: "The pem private key (content or path to a file) of 'client_cert'.",
+			},
+			"max_requests_per_second": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Default:     0.0,
+				Description: "Maximum number of requests per second sent to Cloud Foundry, 0 means unlimited.",
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum number of requests sent to Cloud Foundry in parallel, 0 means unlimited.",
+			},
+			"cache_lookups": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Cache lookups of orgs, spaces, domains, stacks and service plans during a run, cache is dropped on each change made by the provider.",
+			},
+			"read_only": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_READ_ONLY", false),
+				Description: "Refuse every request which could change Cloud Foundry, only lookups are made. Useful to audit a Cloud Foundry with plan and refresh.",
+			},
+			"non_admin": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_NON_ADMIN", false),
+				Description: "User is not a Cloud Foundry admin (e.g.: SpaceDeveloper or OrgManager), lookups are made only in orgs and spaces given and resources which need admin permissions are refused.",
+			},
+			"default_org": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_ORG", ""),
+				Description: "Name of the organization used by resources when 'org_id' is not given.",
+			},
+			"default_space": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CF_SPACE", ""),
+				Description: "Name of the space, inside 'default_org', used by resources when 'space_id' is not given.",
+			},
+			"protected": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Names or guid patterns of objects which can't be deleted, replaced or renamed by the provider.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						cf_client.ProtectedOrgs: &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names or guid patterns of protected organizations.",
+						},
+						cf_client.ProtectedSpaces: &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names or guid patterns of protected spaces.",
+						},
+						cf_client.ProtectedApps: &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names or guid patterns of protected applications.",
+						},
+						cf_client.ProtectedServices: &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names or guid patterns of protected service instances.",
+						},
+						cf_client.ProtectedDomains: &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names or guid patterns of protected domains.",
+						},
+					},
+				},
+			},
+			"retry": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Retry and timeout policy applied to every call made to Cloud Foundry.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_attempts": &schema.Schema{
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     3,
+							Description: "Maximum number of attempts for a call, 1 disables retries.",
+						},
+						"initial_backoff": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "500ms",
+							ValidateFunc: validateDuration,
+							Description:  "Time to wait before the first retry, it is doubled on each retry.",
+						},
+						"max_backoff": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "30s",
+							ValidateFunc: validateDuration,
+							Description:  "Maximum time to wait between two attempts.",
+						},
+						"jitter": &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Randomize time to wait between two attempts.",
+						},
+						"retryable_status_codes": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Description: "Http status codes which make a call retried (default: 429, 502, 503 and 504).",
+						},
+						"dial_timeout": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10s",
+							ValidateFunc: validateDuration,
+							Description:  "Timeout to open a connection to Cloud Foundry.",
+						},
+						"request_timeout": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "2m",
+							ValidateFunc: validateDuration,
+							Description:  "Timeout to wait for Cloud Foundry to answer a request.",
+						},
+						"job_timeout": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "30m",
+							ValidateFunc: validateDuration,
+							Description:  "Timeout to wait for Cloud Foundry asynchronous jobs to finish.",
+						},
+					},
+				},
+			},
 		},
 
-		ResourcesMap: map[string]*schema.Resource{
+		ResourcesMap: resources.AddressResources(map[string]*schema.Resource{
 			"cloudfoundry_organization":                  resources.LoadCfResource(resources.CfOrganizationResource{}),
 			"cloudfoundry_space":                         resources.LoadCfResource(resources.CfSpaceResource{}),
 			"cloudfoundry_quota":                         resources.LoadCfResource(resources.CfQuotaResource{}),
@@ -87,7 +322,7 @@ func Provider() terraform.ResourceProvider {
 			"cloudfoundry_isolation_segment_space":       resources.LoadCfResourceNoUpdate(resources.CfIsolationSegmentSpaceResource{}),
 			"cloudfoundry_env_var_group":                 resources.LoadCfResource(resources.CfEnvVarGroupResource{}),
 			"cloudfoundry_app":                           resources.LoadCfResource(resources.CfAppsResource{}),
-		},
+		}),
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"cloudfoundry_organization":      resources.LoadCfDataSource(resources.CfOrganizationResource{}),
@@ -106,36 +341,131 @@ func Provider() terraform.ResourceProvider {
 			"cloudfoundry_stack":             resources.LoadCfDataSource(resources.CfStackResource{}),
 			"cloudfoundry_app":               resources.LoadCfDataSource(resources.CfAppsResource{}),
 		},
-
-		ConfigureFunc: providerConfigure,
 	}
+	// client stops in-flight requests when terraform is interrupted
+	var client cf_client.Client
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		// files opened by a previous configuration are not used anymore
+		if client != nil {
+			client.Close()
+		}
+		meta, err := providerConfigure(provider.StopContext(), d)
+		if err != nil {
+			return nil, err
+		}
+		client = meta.(cf_client.Client)
+		return client, nil
+	}
+	return provider
 }
 func main() {
 	cfProvider := &plugin.ServeOpts{ProviderFunc: Provider}
 	plugin.Serve(cfProvider)
 }
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, error) {
 	config := cf_client.Config{
-		AppName:          "tf-provider",
-		AppVersion:       "0.10.0",
-		ApiEndpoint:      d.Get("api_endpoint").(string),
-		Username:         d.Get("username").(string),
-		Password:         d.Get("password").(string),
-		UserRefreshToken: parseToken(d.Get("user_refresh_token").(string)),
-		UserAccessToken:  parseToken(d.Get("user_access_token").(string)),
-		Locale:           "en_US",
-		Verbose:          d.Get("verbose").(bool),
-		SkipInsecureSSL:  d.Get("skip_ssl_validation").(bool),
-		EncPrivateKey:    d.Get("enc_private_key").(string),
-		Passphrase:       d.Get("enc_passphrase").(string),
-	}
-	if config.UserAccessToken == "" && (config.Username == "" || config.Password == "") {
-		return nil, errors.New("You must provide an 'user_access_token' or an admin 'username' and 'password'")
+		AppName:               "tf-provider",
+		AppVersion:            "0.10.0",
+		ApiEndpoint:           d.Get("api_endpoint").(string),
+		Username:              d.Get("username").(string),
+		Password:              d.Get("password").(string),
+		UaaClientID:           d.Get("client_id").(string),
+		UaaClientSecret:       d.Get("client_secret").(string),
+		Origin:                d.Get("origin").(string),
+		UserRefreshToken:      parseToken(d.Get("user_refresh_token").(string)),
+		UserAccessToken:       parseToken(d.Get("user_access_token").(string)),
+		AccessTokenFile:       d.Get("access_token_file").(string),
+		RefreshTokenFile:      d.Get("refresh_token_file").(string),
+		Locale:                "en_US",
+		Verbose:               d.Get("verbose").(bool),
+		TraceFile:             d.Get("trace_file").(string),
+		JournalFile:           d.Get("journal_file").(string),
+		SkipInsecureSSL:       d.Get("skip_ssl_validation").(bool),
+		CACert:                d.Get("ca_cert").(string),
+		ClientCert:            d.Get("client_cert").(string),
+		ClientKey:             d.Get("client_key").(string),
+		HTTPProxy:             d.Get("http_proxy").(string),
+		HTTPSProxy:            d.Get("https_proxy").(string),
+		NoProxy:               d.Get("no_proxy").(string),
+		EncPrivateKey:         d.Get("enc_private_key").(string),
+		Passphrase:            d.Get("enc_passphrase").(string),
+		Retry:                 retryPolicy(d),
+		MaxRequestsPerSecond:  d.Get("max_requests_per_second").(float64),
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+		CacheLookups:          d.Get("cache_lookups").(bool),
+		ReadOnly:              d.Get("read_only").(bool),
+		NonAdmin:              d.Get("non_admin").(bool),
+		Protected:             protectedObjects(d),
+		DefaultOrg:            d.Get("default_org").(string),
+		DefaultSpace:          d.Get("default_space").(string),
+	}
+	err := config.Protected.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = config.LoadTokenFiles()
+	if err != nil {
+		return nil, err
+	}
+	err = config.LoadCliConfig(d.Get("cf_config_path").(string))
+	if err != nil {
+		return nil, err
+	}
+	if (config.UaaClientID == "") != (config.UaaClientSecret == "") {
+		return nil, errors.New("You must provide both 'client_id' and 'client_secret' to use a client_credentials grant.")
+	}
+	if config.UserAccessToken == "" && !config.IsClientCredentials() && (config.Username == "" || config.Password == "") {
+		return nil, errors.New("You must provide an 'user_access_token' or 'access_token_file', a 'client_id' and 'client_secret', a 'username' and 'password' or log in with the cf cli first")
+	}
+	if config.DefaultSpace != "" && config.DefaultOrg == "" {
+		return nil, errors.New("You must provide a 'default_org' to use a 'default_space'.")
 	}
 	if config.EncPrivateKey != "" && config.Passphrase == "" {
 		return nil, errors.New("You must provide an 'enc_passphrase' to use a gpg key.")
 	}
-	return cf_client.NewCfClient(config)
+	return cf_client.NewCfClient(ctx, config)
+}
+func retryPolicy(d *schema.ResourceData) common.RetryPolicy {
+	policy := common.DefaultRetryPolicy()
+	retries := d.Get("retry").([]interface{})
+	if len(retries) == 0 || retries[0] == nil {
+		return policy
+	}
+	retry := retries[0].(map[string]interface{})
+	policy.MaxAttempts = retry["max_attempts"].(int)
+	policy.InitialBackoff, _ = time.ParseDuration(retry["initial_backoff"].(string))
+	policy.MaxBackoff, _ = time.ParseDuration(retry["max_backoff"].(string))
+	policy.Jitter = retry["jitter"].(bool)
+	policy.DialTimeout, _ = time.ParseDuration(retry["dial_timeout"].(string))
+	policy.RequestTimeout, _ = time.ParseDuration(retry["request_timeout"].(string))
+	policy.JobTimeout, _ = time.ParseDuration(retry["job_timeout"].(string))
+	if statusCodes := retry["retryable_status_codes"].([]interface{}); len(statusCodes) > 0 {
+		policy.RetryableStatusCodes = make([]int, len(statusCodes))
+		for i, statusCode := range statusCodes {
+			policy.RetryableStatusCodes[i] = statusCode.(int)
+		}
+	}
+	return policy
+}
+func protectedObjects(d *schema.ResourceData) cf_client.ProtectedObjects {
+	protected := make(cf_client.ProtectedObjects)
+	blocks := d.Get("protected").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return protected
+	}
+	for kind, patterns := range blocks[0].(map[string]interface{}) {
+		for _, pattern := range patterns.([]interface{}) {
+			protected[kind] = append(protected[kind], pattern.(string))
+		}
+	}
+	return protected
+}
+func validateDuration(elem interface{}, key string) ([]string, []error) {
+	_, err := time.ParseDuration(elem.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s must be a duration (e.g.: 30s, 2m): %s", key, err.Error())}
+	}
+	return nil, nil
 }
 func parseToken(token string) string {
 	if token == "" {
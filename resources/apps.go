@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3"
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3/constant"
 	"code.cloudfoundry.org/cli/cf/errors"
 	"code.cloudfoundry.org/cli/cf/formatters"
 	"code.cloudfoundry.org/cli/cf/models"
@@ -12,6 +14,8 @@ import (
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/rewind"
 	"github.com/viant/toolbox"
 	"log"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -21,6 +25,17 @@ const (
 	stateStarted = "STARTED"
 )
 
+const (
+	strategyBlueGreen = "blue-green"
+	strategyRolling   = "rolling"
+	strategyStopStart = "stop-start"
+)
+
+// deploymentTimeout is the maximum time given to cloud controller to replace every instance of an app
+const deploymentTimeout = 30 * time.Minute
+
+var deploymentStrategies = []string{strategyBlueGreen, strategyRolling, strategyStopStart}
+
 type CfAppsResource struct{}
 type AppParams struct {
 	models.AppParams
@@ -88,13 +103,18 @@ func (c CfAppsResource) MakeBitsManager(meta interface{}) bitsmanager.BitsManage
 		client.ApplicationBits(),
 		[]bitsmanager.Handler{
 			bitsmanager.NewLocalHandler(),
-			bitsmanager.NewHttpHandler(client.Config().SkipInsecureSSL),
-			bitsmanager.NewGitHandler(client.Config().SkipInsecureSSL),
+			bitsmanager.NewHttpHandler(client.TLSConfig(), client.Proxy()),
+			bitsmanager.NewGitHandler(client.TLSConfig(), client.Proxy()),
 		},
+		client.Config().Retry,
 	)
 }
 func (c CfAppsResource) Create(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
+	err := SetDefaultSpaceID(d, meta)
+	if err != nil {
+		return err
+	}
 	if ok, _ := c.Exists(d, meta); ok {
 		log.Printf(
 			"[INFO] updating app %s/%s instead of creating it because it already exists on your Cloud Foundry",
@@ -111,7 +131,7 @@ func (c CfAppsResource) createOrUpdate(d *schema.ResourceData, meta interface{})
 		return c.createApp(d, meta, d.Get("started").(bool), true)
 	}
 	if c.IsRoutesUpdate(d) {
-		app, err := client.Finder().GetAppFromCf(d.Id())
+		app, err := client.Finder().GetAppFromCf(client.Context(), d.Id())
 		if err != nil {
 			return err
 		}
@@ -133,7 +153,19 @@ func (c CfAppsResource) createOrUpdate(d *schema.ResourceData, meta interface{})
 		_, err := client.Applications().Update(d.Id(), models.AppParams{Name: &name, InstanceCount: &instances})
 		return err
 	}
-	if c.IsBitsDiff(d) && d.Get("no_blue_green_deploy").(bool) {
+	if c.IsStrategyUpdate(d) {
+		// strategy is used on next deployment only
+		return nil
+	}
+	if c.IsProcessesUpdate(d) {
+		// other process types are not restarted
+		return c.updateProcesses(client, d)
+	}
+	strategy := c.deploymentStrategy(d)
+	if strategy == strategyRolling {
+		return c.updateRolling(d, meta)
+	}
+	if c.IsBitsDiff(d) && (strategy == strategyStopStart || d.Get("no_blue_green_deploy").(bool)) {
 		a := models.Application{}
 		a.GUID = d.Id()
 		err := c.stopApp(client, a)
@@ -144,12 +176,20 @@ func (c CfAppsResource) createOrUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return err
 		}
-		return c.startApp(client, a)
+		err = c.updateSidecars(client, d)
+		if err != nil {
+			return err
+		}
+		err = c.startApp(client, a)
+		if err != nil {
+			return err
+		}
+		return c.updateProcesses(client, d)
 	}
 	if c.IsBitsDiff(d) {
 		return c.updateBgDeploy(d, meta)
 	}
-	if d.Get("no_blue_green_restage").(bool) {
+	if strategy == strategyStopStart || d.Get("no_blue_green_restage").(bool) {
 		appParams, err := c.resourceObject(d)
 		if err != nil {
 			return err
@@ -160,31 +200,200 @@ func (c CfAppsResource) createOrUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return err
 		}
-		return c.restartApp(client, a)
+		err = c.updateSidecars(client, d)
+		if err != nil {
+			return err
+		}
+		err = c.restartApp(client, a)
+		if err != nil {
+			return err
+		}
+		return c.updateProcesses(client, d)
 	}
 	return c.updateBgRestage(d, meta)
 }
-func (c CfAppsResource) updateBg(actionList []rewind.Action) error {
+func (c CfAppsResource) updateBg(client cf_client.Client, actionList []rewind.Action) error {
 	actions := rewind.Actions{
 		Actions:              actionList,
 		RewindFailureMessage: "Oh no. Something's gone wrong. I've tried to roll back but you should check to see if everything is OK.",
 	}
-	return actions.Execute()
+	return actions.ExecuteContext(client.Context())
 }
 func (c CfAppsResource) updateBgRestage(d *schema.ResourceData, meta interface{}) error {
-	err := c.updateBg(c.rewindActionsBgRestage(d, meta))
+	err := c.updateBg(meta.(cf_client.Client), c.rewindActionsBgRestage(d, meta))
 	if err != nil {
 		return fmt.Errorf("Error when trying to update the app %s in blue-green restage mode: %s", d.Get("name").(string), err.Error())
 	}
 	return nil
 }
 func (c CfAppsResource) updateBgDeploy(d *schema.ResourceData, meta interface{}) error {
-	err := c.updateBg(c.rewindActionsBgDeploy(d, meta))
+	err := c.updateBg(meta.(cf_client.Client), c.rewindActionsBgDeploy(d, meta))
 	if err != nil {
 		return fmt.Errorf("Error when trying to update the app %s in blue-green deploy mode: %s", d.Get("name").(string), err.Error())
 	}
 	return nil
 }
+func (c CfAppsResource) deploymentStrategy(d ResourceGetter) string {
+	strategy, _ := d.Get("deployment_strategy").(string)
+	if strategy == "" {
+		return strategyBlueGreen
+	}
+	return strategy
+}
+
+// updateRolling updates app in place and replaces its instances one by one with a cloud controller v3 deployment,
+// app keeps its guid and deployment is canceled when it can't be finished.
+// Settings which need new instances are only kept in state once they run, a failed deployment is done again on next apply.
+func (c CfAppsResource) updateRolling(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(cf_client.Client)
+	appParams, err := c.resourceObject(d)
+	if err != nil {
+		return err
+	}
+	d.Partial(true)
+	wasStarted, _ := d.GetChange("started")
+	started := d.Get("started").(bool)
+	if started {
+		// running instances are replaced by deployment, app must not be stopped
+		appParams.State = nil
+	}
+	_, err = client.Applications().Update(d.Id(), appParams.AppParams)
+	if err != nil {
+		return err
+	}
+	a := models.Application{}
+	a.GUID = d.Id()
+	a.Name = d.Get("name").(string)
+	a.InstanceCount = d.Get("instances").(int)
+	err = c.updateRoutes(d, meta, a)
+	if err != nil {
+		return err
+	}
+	// routes are used as soon as they are mapped
+	d.SetPartial("routes")
+	currentServices := make([]string, 0)
+	if d.HasChange("services") {
+		currentTfServices, _ := d.GetChange("services")
+		currentServices = common.SchemaSetToStringList(currentTfServices.(*schema.Set))
+	}
+	err = c.BindServices(client, a, appParams.ServiceIds, currentServices)
+	if err != nil {
+		return err
+	}
+	err = c.updateSidecars(client, d)
+	if err != nil {
+		return err
+	}
+	if c.IsBitsDiff(d) {
+		err = c.SendBits(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+	if started {
+		err = c.deployRolling(client, d, a, wasStarted.(bool))
+		if err != nil {
+			return err
+		}
+	}
+	d.Partial(false)
+	return nil
+}
+func (c CfAppsResource) deployRolling(client cf_client.Client, d *schema.ResourceData, a models.Application, wasStarted bool) error {
+	if !wasStarted {
+		// a stopped app can't be deployed, it is only staged and started
+		err := c.startApp(client, a)
+		if err != nil {
+			return err
+		}
+		return c.updateProcesses(client, d)
+	}
+	err := c.rollingDeploy(client, a)
+	if err != nil {
+		return fmt.Errorf("Error when trying to update the app %s in rolling mode: %s", a.Name, err.Error())
+	}
+	return c.updateProcesses(client, d)
+}
+func (c CfAppsResource) rollingDeploy(client cf_client.Client, a models.Application) error {
+	dropletGuid, err := c.stageLatestPackage(client, a)
+	if err != nil {
+		return c.createErrorFromLog(err, client, a)
+	}
+	return c.deployDroplet(client, a, dropletGuid)
+}
+
+// deployDroplet replaces instances of app by instances running droplet and waits until every instance runs it,
+// deployment is canceled when it can't be finished.
+func (c CfAppsResource) deployDroplet(client cf_client.Client, a models.Application, dropletGuid string) error {
+	deployment, err := client.Deployments().Create(a.GUID, dropletGuid)
+	if err != nil {
+		return err
+	}
+	err = common.PollingWithTimeout(client.Context(), func() (bool, error) {
+		current, err := client.Deployments().Get(deployment.GUID)
+		if err != nil {
+			return true, err
+		}
+		deployment = current
+		if !deployment.IsFinished() {
+			return false, nil
+		}
+		if deployment.IsDeployed() {
+			return true, nil
+		}
+		return true, fmt.Errorf("Deployment %s of app %s finished with status %s", deployment.GUID, a.Name, deployment.Status())
+	}, 5*time.Second, deploymentTimeout)
+	if err == nil {
+		return nil
+	}
+	if !deployment.IsFinished() {
+		// instances already replaced go back to previous droplet, even when apply is interrupted
+		cancelErr := client.Compensate(func(client cf_client.Client) error {
+			return client.Deployments().Cancel(deployment.GUID)
+		})
+		if cancelErr != nil {
+			return fmt.Errorf("%s (deployment %s can't be canceled: %s)", err.Error(), deployment.GUID, cancelErr.Error())
+		}
+	}
+	return c.createErrorFromLog(err, client, a)
+}
+
+// stageLatestPackage stages the last package uploaded for app and gives droplet created
+func (c CfAppsResource) stageLatestPackage(client cf_client.Client, a models.Application) (string, error) {
+	packages, _, err := client.CCv3Client().GetPackages(
+		ccv3.Query{Key: ccv3.AppGUIDFilter, Values: []string{a.GUID}},
+		ccv3.Query{Key: "states", Values: []string{string(constant.PackageReady)}},
+		ccv3.Query{Key: ccv3.OrderBy, Values: []string{"-created_at"}},
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(packages) == 0 {
+		return "", fmt.Errorf("No package ready to be staged for app %s", a.Name)
+	}
+	build, _, err := client.CCv3Client().CreateBuild(ccv3.Build{PackageGUID: packages[0].GUID})
+	if err != nil {
+		return "", err
+	}
+	err = common.PollingWithTimeout(client.Context(), func() (bool, error) {
+		current, _, err := client.CCv3Client().GetBuild(build.GUID)
+		if err != nil {
+			return true, err
+		}
+		build = current
+		if build.State == constant.BuildStaged {
+			return true, nil
+		}
+		if build.State == constant.BuildFailed {
+			return true, fmt.Errorf("Staging failed for app %s: %s", a.Name, build.Error)
+		}
+		return false, nil
+	}, 5*time.Second, 15*time.Minute)
+	if err != nil {
+		return "", err
+	}
+	return build.DropletGUID, nil
+}
 func (c CfAppsResource) updateRoutes(d *schema.ResourceData, meta interface{}, a models.Application) error {
 	client := meta.(cf_client.Client)
 	currentRoutes := make([]string, 0)
@@ -195,6 +404,11 @@ func (c CfAppsResource) updateRoutes(d *schema.ResourceData, meta interface{}, a
 	return c.BindRoutes(client, a, common.SchemaSetToStringList(d.Get("routes").(*schema.Set)), currentRoutes)
 }
 func (c CfAppsResource) createApp(d *schema.ResourceData, meta interface{}, started bool, sendBits bool) error {
+	return c.createAppWithRoutes(d, meta, common.SchemaSetToStringList(d.Get("routes").(*schema.Set)), started, sendBits)
+}
+
+// createAppWithRoutes creates app only mapped to given routes
+func (c CfAppsResource) createAppWithRoutes(d *schema.ResourceData, meta interface{}, routes []string, started bool, sendBits bool) error {
 	client := meta.(cf_client.Client)
 	appParams, err := c.resourceObject(d)
 	if err != nil {
@@ -205,7 +419,7 @@ func (c CfAppsResource) createApp(d *schema.ResourceData, meta interface{}, star
 		return err
 	}
 	d.SetId(app.GUID)
-	err = c.updateRoutes(d, meta, app)
+	err = c.BindRoutes(client, app, routes, []string{})
 	if err != nil {
 		return err
 	}
@@ -218,6 +432,11 @@ func (c CfAppsResource) createApp(d *schema.ResourceData, meta interface{}, star
 	if err != nil {
 		return err
 	}
+	// sidecars must exist before app is started to run with its instances
+	err = c.updateSidecars(client, d)
+	if err != nil {
+		return err
+	}
 
 	if sendBits {
 		err = c.SendBits(d, meta)
@@ -232,7 +451,7 @@ func (c CfAppsResource) createApp(d *schema.ResourceData, meta interface{}, star
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.updateProcesses(client, d)
 }
 func (c CfAppsResource) stopApp(client cf_client.Client, a models.Application) error {
 	state := stateStopped
@@ -243,82 +462,280 @@ func (c CfAppsResource) stopApp(client cf_client.Client, a models.Application) e
 	return nil
 }
 func (c CfAppsResource) rewindActionsBgDeploy(d *schema.ResourceData, meta interface{}) []rewind.Action {
-	client := meta.(cf_client.Client)
-	oldAppName, newAppName := d.GetChange("name")
-	origAppName := oldAppName.(string)
-	if origAppName == "" {
-		origAppName = newAppName.(string)
-	}
-	origAppGuid := d.Id()
-	return []rewind.Action{
-		{
-			Forward: func() error {
-				return c.renameApplication(client, origAppGuid, venerableAppName(origAppName))
-			},
-		},
-		{
-			Forward: func() error {
-				return c.createApp(d, meta, d.Get("started").(bool), true)
-			},
-			ReversePrevious: func() error {
-				client.Applications().Delete(d.Id())
-				return c.renameApplication(client, origAppGuid, origAppName)
-			},
-		},
-		{
-			Forward: func() error {
-				return client.Applications().Delete(origAppGuid)
-			},
-		},
-	}
+	swap := c.newBlueGreenSwap(d, meta)
+	return swap.actions(func() error {
+		return c.createAppWithRoutes(d, meta, []string{}, false, true)
+	})
 }
 func (c CfAppsResource) rewindActionsBgRestage(d *schema.ResourceData, meta interface{}) []rewind.Action {
-	client := meta.(cf_client.Client)
+	swap := c.newBlueGreenSwap(d, meta)
 	bm := c.MakeBitsManager(meta)
+	return swap.actions(func() error {
+		err := c.createAppWithRoutes(d, meta, []string{}, false, false)
+		if err != nil {
+			return err
+		}
+		return bm.CopyBits(swap.origAppGuid, d.Id())
+	})
+}
+
+// blueGreenSwap replaces an app by a new one, production routes are only moved to new app
+// when all its instances are running. It remembers what was done to be able to rewind it.
+type blueGreenSwap struct {
+	c           CfAppsResource
+	d           *schema.ResourceData
+	meta        interface{}
+	client      cf_client.Client
+	origAppGuid string
+	origAppName string
+	// routes are production routes mapped to orig app before swap
+	routes         []string
+	tempRouteGuid  string
+	tempRouteURL   string
+	unmappedRoutes []string
+	origStopped    bool
+}
+
+func (c CfAppsResource) newBlueGreenSwap(d *schema.ResourceData, meta interface{}) *blueGreenSwap {
 	oldAppName, newAppName := d.GetChange("name")
 	origAppName := oldAppName.(string)
 	if origAppName == "" {
 		origAppName = newAppName.(string)
 	}
-	origAppGuid := d.Id()
-	defaultReverse := func() error {
-		client.Applications().Delete(d.Id())
-		return c.renameApplication(client, origAppGuid, origAppName)
+	return &blueGreenSwap{
+		c:           c,
+		d:           d,
+		meta:        meta,
+		client:      meta.(cf_client.Client),
+		origAppGuid: d.Id(),
+		origAppName: origAppName,
 	}
+}
+func (s *blueGreenSwap) actions(createNewApp func() error) []rewind.Action {
+	started := s.d.Get("started").(bool)
 	return []rewind.Action{
 		{
 			Forward: func() error {
-				return c.renameApplication(client, origAppGuid, venerableAppName(origAppName))
+				return s.c.renameApplication(s.client, s.origAppGuid, venerableAppName(s.origAppName))
 			},
 		},
+		{
+			Forward:         createNewApp,
+			ReversePrevious: s.rewind,
+		},
 		{
 			Forward: func() error {
-				return c.createApp(d, meta, false, false)
+				if !started {
+					return nil
+				}
+				return s.mapTemporaryRoute()
 			},
-			ReversePrevious: defaultReverse,
+			ReversePrevious: s.rewind,
 		},
 		{
 			Forward: func() error {
-				return bm.CopyBits(origAppGuid, d.Id())
+				if !started {
+					return nil
+				}
+				return s.startNewApp()
 			},
-			ReversePrevious: defaultReverse,
+			ReversePrevious: s.rewind,
 		},
 		{
 			Forward: func() error {
-				if !d.Get("started").(bool) {
+				if !started {
 					return nil
 				}
-				return c.startApp(client, models.Application{ApplicationFields: models.ApplicationFields{GUID: d.Id()}})
+				return s.smokeTest()
 			},
-			ReversePrevious: defaultReverse,
+			ReversePrevious: s.rewind,
+		},
+		{
+			Forward:         s.moveRoutes,
+			ReversePrevious: s.rewind,
+		},
+		{
+			Forward:         s.stopOrigApp,
+			ReversePrevious: s.rewind,
+		},
+		{
+			Forward:         s.deleteTemporaryRoute,
+			ReversePrevious: s.rewind,
 		},
 		{
 			Forward: func() error {
-				return client.Applications().Delete(origAppGuid)
+				return s.client.Applications().Delete(s.origAppGuid)
 			},
 		},
 	}
 }
+func (s *blueGreenSwap) newApp() models.Application {
+	a := models.Application{}
+	a.GUID = s.d.Id()
+	a.Name = s.d.Get("name").(string)
+	a.InstanceCount = s.d.Get("instances").(int)
+	return a
+}
+
+// mapTemporaryRoute gives access to new app before it receives production traffic,
+// it is created on the domain of the first production route.
+func (s *blueGreenSwap) mapTemporaryRoute() error {
+	routes := common.SchemaSetToStringList(s.d.Get("routes").(*schema.Set))
+	if len(routes) == 0 {
+		return nil
+	}
+	prodRoute, err := s.client.Finder().GetRouteFromCf(s.client.Context(), routes[0])
+	if err != nil {
+		return err
+	}
+	host := temporaryRouteHost(s.d.Get("name").(string), s.d.Id())
+	randomPort := prodRoute.Port != 0
+	if randomPort {
+		host = ""
+	}
+	route, err := s.client.Route().CreateInSpace(host, "", prodRoute.Domain.GUID, s.d.Get("space_id").(string), 0, randomPort)
+	if err != nil {
+		return err
+	}
+	s.tempRouteGuid = route.GUID
+	tempRoute := models.Route{Host: route.Host, Domain: prodRoute.Domain, Port: route.Port}
+	s.tempRouteURL = "https://" + tempRoute.URL()
+	if randomPort {
+		s.tempRouteURL = "http://" + tempRoute.URL()
+	}
+	return s.client.Route().Bind(route.GUID, s.d.Id())
+}
+
+// smokeTest probes new app through temporary route, routes are not moved when it doesn't pass
+func (s *blueGreenSwap) smokeTest() error {
+	smokeTest, ok := s.c.smokeTestFromSchema(s.d)
+	if !ok {
+		return nil
+	}
+	if s.tempRouteURL == "" {
+		return fmt.Errorf("Smoke test can't be done on app %s: it needs at least one route", s.d.Get("name").(string))
+	}
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           s.client.Proxy(),
+			TLSClientConfig: s.client.TLSConfig(),
+		},
+		Timeout: 30 * time.Second,
+	}
+	return smokeTest.Probe(s.client.Context(), httpClient, s.tempRouteURL)
+}
+
+// startNewApp is the health gate, production routes are not moved before every instance configured is running
+func (s *blueGreenSwap) startNewApp() error {
+	a := s.newApp()
+	err := s.c.startApp(s.client, a)
+	if err != nil {
+		return err
+	}
+	err = s.c.updateProcesses(s.client, s.d)
+	if err != nil {
+		return err
+	}
+	return s.c.waitInstancesRunning(s.client, a, a.InstanceCount)
+}
+func (s *blueGreenSwap) moveRoutes() error {
+	origApp, err := s.client.Finder().GetAppFromCf(s.client.Context(), s.origAppGuid)
+	if err != nil {
+		return err
+	}
+	for _, route := range origApp.Routes {
+		s.routes = append(s.routes, route.GUID)
+	}
+	err = s.c.BindRoutes(s.client, s.newApp(), common.SchemaSetToStringList(s.d.Get("routes").(*schema.Set)), []string{})
+	if err != nil {
+		return err
+	}
+	for _, route := range s.routes {
+		err := s.client.Route().Unbind(route, s.origAppGuid)
+		if err != nil {
+			return err
+		}
+		s.unmappedRoutes = append(s.unmappedRoutes, route)
+	}
+	return nil
+}
+func (s *blueGreenSwap) stopOrigApp() error {
+	a := models.Application{}
+	a.GUID = s.origAppGuid
+	err := s.c.stopApp(s.client, a)
+	if err != nil {
+		return err
+	}
+	s.origStopped = true
+	return nil
+}
+func (s *blueGreenSwap) deleteTemporaryRoute() error {
+	return s.deleteTemporaryRouteWith(s.client)
+}
+func (s *blueGreenSwap) deleteTemporaryRouteWith(client cf_client.Client) error {
+	if s.tempRouteGuid == "" {
+		return nil
+	}
+	err := client.Route().Delete(s.tempRouteGuid)
+	if err != nil {
+		return err
+	}
+	s.tempRouteGuid = ""
+	s.tempRouteURL = ""
+	return nil
+}
+
+// rewind brings back orig app with its routes and removes everything created for new app,
+// it goes on when a step fails to let as few things as possible behind and gives first error.
+// It is still done when apply is interrupted.
+func (s *blueGreenSwap) rewind() error {
+	return s.client.Compensate(s.rewindWith)
+}
+func (s *blueGreenSwap) rewindWith(client cf_client.Client) error {
+	errs := make([]error, 0)
+	if s.origStopped {
+		a := models.Application{}
+		a.GUID = s.origAppGuid
+		a.Name = s.origAppName
+		errs = append(errs, s.c.startApp(client, a))
+	}
+	for _, route := range s.unmappedRoutes {
+		errs = append(errs, client.Route().Bind(route, s.origAppGuid))
+	}
+	errs = append(errs, s.deleteTemporaryRouteWith(client))
+	// new app may not be created when apply was interrupted
+	if s.d.Id() != s.origAppGuid {
+		errs = append(errs, client.Applications().Delete(s.d.Id()))
+		s.d.SetId(s.origAppGuid)
+	}
+	errs = append(errs, s.c.renameApplication(client, s.origAppGuid, s.origAppName))
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// temporaryRouteHost gives a valid hostname for the route used to reach new app before swapping routes,
+// it ends with the start of new app guid to not collide with a route left behind or an app of another space with the same name.
+func temporaryRouteHost(appName, newAppGuid string) string {
+	host := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(appName))
+	host = strings.Trim(host, "-")
+	if len(host) > 44 {
+		host = strings.TrimRight(host[:44], "-")
+	}
+	suffix := strings.ToLower(strings.Replace(newAppGuid, "-", "", -1))
+	if len(suffix) > 8 {
+		suffix = suffix[:8]
+	}
+	return host + "-temporary-" + suffix
+}
 func venerableAppName(appName string) string {
 	return fmt.Sprintf("%s-venerable", appName)
 }
@@ -341,10 +758,10 @@ func (c CfAppsResource) restartApp(client cf_client.Client, a models.Application
 	}
 	return nil
 }
-func (c CfAppsResource) IsRoutesUpdate(d *schema.ResourceData) bool {
+func (c CfAppsResource) IsRoutesUpdate(d ResourceChangeGetter) bool {
 	return c.IsKeyUpdate(d, "routes")
 }
-func (c CfAppsResource) IsKeyUpdate(d *schema.ResourceData, key string) bool {
+func (c CfAppsResource) IsKeyUpdate(d ResourceChangeGetter, key string) bool {
 	if !d.HasChange(key) {
 		return false
 	}
@@ -355,7 +772,7 @@ func (c CfAppsResource) IsKeyUpdate(d *schema.ResourceData, key string) bool {
 	}
 	return true
 }
-func (c CfAppsResource) IsScaleAndRename(d *schema.ResourceData) bool {
+func (c CfAppsResource) IsScaleAndRename(d ResourceChangeGetter) bool {
 	if !d.HasChange("instances") || !d.HasChange("name") {
 		return false
 	}
@@ -366,10 +783,32 @@ func (c CfAppsResource) IsScaleAndRename(d *schema.ResourceData) bool {
 	}
 	return true
 }
-func (c CfAppsResource) IsRenameUpdate(d *schema.ResourceData) bool {
+
+// IsStrategyUpdate is true when only the way to deploy app has changed, nothing has to be done on Cloud Foundry
+func (c CfAppsResource) IsStrategyUpdate(d ResourceChangeGetter) bool {
+	if c.IsBitsDiff(d) {
+		return false
+	}
+	strategyKeys := []string{"deployment_strategy", "no_blue_green_deploy", "no_blue_green_restage", "smoke_test"}
+	changed := false
+	for schemaKey, _ := range c.Schema() {
+		if !d.HasChange(schemaKey) {
+			continue
+		}
+		if !toolbox.HasSliceAnyElements(strategyKeys, schemaKey) {
+			return false
+		}
+		changed = true
+	}
+	return changed
+}
+func (c CfAppsResource) IsProcessesUpdate(d ResourceChangeGetter) bool {
+	return c.IsKeyUpdate(d, "process")
+}
+func (c CfAppsResource) IsRenameUpdate(d ResourceChangeGetter) bool {
 	return c.IsKeyUpdate(d, "name")
 }
-func (c CfAppsResource) IsScaleUpdate(d *schema.ResourceData) bool {
+func (c CfAppsResource) IsScaleUpdate(d ResourceChangeGetter) bool {
 	return c.IsKeyUpdate(d, "instances")
 }
 func (c CfAppsResource) startApp(client cf_client.Client, a models.Application) error {
@@ -378,7 +817,7 @@ func (c CfAppsResource) startApp(client cf_client.Client, a models.Application)
 	if err != nil {
 		return err
 	}
-	err = common.PollingWithTimeout(func() (bool, error) {
+	err = common.PollingWithTimeout(client.Context(), func() (bool, error) {
 		app, err := client.Applications().GetApp(a.GUID)
 		if err != nil {
 			return true, err
@@ -387,14 +826,16 @@ func (c CfAppsResource) startApp(client cf_client.Client, a models.Application)
 			return true, nil
 		}
 		if app.PackageState == "FAILED" {
-			return true, fmt.Errorf("Staging failed for app %s", a.Name)
+			return true, &cf_client.CCError{
+				Description: fmt.Sprintf("Staging failed for app %s: %s", a.Name, app.StagingFailedReason),
+			}
 		}
 		return false, nil
 	}, 5*time.Second, 15*time.Minute)
 	if err != nil {
 		return c.createErrorFromLog(err, client, a)
 	}
-	err = common.Polling(func() (bool, error) {
+	err = common.Polling(client.Context(), func() (bool, error) {
 		appInstances, err := client.AppInstances().GetInstances(a.GUID)
 		if err != nil {
 			return true, err
@@ -419,22 +860,49 @@ func (c CfAppsResource) startApp(client cf_client.Client, a models.Application)
 	}
 	return nil
 }
+
+// waitInstancesRunning waits until the number of instances given are running, it fails as soon as an instance crashes
+func (c CfAppsResource) waitInstancesRunning(client cf_client.Client, a models.Application, instances int) error {
+	err := common.PollingWithTimeout(client.Context(), func() (bool, error) {
+		appInstances, err := client.AppInstances().GetInstances(a.GUID)
+		if err != nil {
+			return true, err
+		}
+		running := 0
+		for i, instance := range appInstances {
+			if instance.State == models.InstanceCrashed || instance.State == models.InstanceFlapping {
+				return true, fmt.Errorf("Instance %d failed with state %s for app %s", i, instance.State, a.Name)
+			}
+			if instance.State == models.InstanceRunning {
+				running++
+			}
+		}
+		return running >= instances, nil
+	}, 5*time.Second, 15*time.Minute)
+	if err != nil {
+		return c.createErrorFromLog(err, client, a)
+	}
+	return nil
+}
 func (c CfAppsResource) createErrorFromLog(parentErr error, client cf_client.Client, a models.Application) error {
+	ccErr := cf_client.NewCCError(parentErr, "").(*cf_client.CCError)
 	loggables, logErr := client.Logs().RecentLogsFor(a.GUID)
 	if logErr != nil {
-		return fmt.Errorf("%s and failed to retrieve logs (error: %s)", parentErr.Error(), logErr.Error())
+		ccErr.Description = fmt.Sprintf("%s and failed to retrieve logs (error: %s)", ccErr.Description, logErr.Error())
+		return ccErr
 	}
 	logs := ""
 	for _, loggable := range loggables {
 		logs += "\n\t" + loggable.ToSimpleLog()
 	}
-	return fmt.Errorf("%s:%s", parentErr.Error(), logs)
+	ccErr.Description = fmt.Sprintf("%s:%s", ccErr.Description, logs)
+	return ccErr
 }
 func (c CfAppsResource) BindServices(client cf_client.Client, a models.Application, newServices, currentServices []string) error {
 	if len(newServices) == 0 {
 		return nil
 	}
-	currentBindings, err := client.Finder().GetServiceBindingsFromApp(a.GUID)
+	currentBindings, err := client.Finder().GetServiceBindingsFromApp(client.Context(), a.GUID)
 	if err != nil {
 		return err
 	}
@@ -511,11 +979,11 @@ func (c CfAppsResource) BindRoutes(client cf_client.Client, a models.Application
 func (c CfAppsResource) SendBits(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	bm := c.MakeBitsManager(meta)
-	err := bm.Upload(d.Id(), d.Get("path").(string))
+	err := bm.Upload(client.Context(), d.Id(), d.Get("path").(string))
 	if err != nil {
 		return err
 	}
-	localSha1, err := bm.GetSha1(d.Get("path").(string))
+	localSha1, err := bm.GetSha1(client.Context(), d.Get("path").(string))
 	if err != nil {
 		return err
 	}
@@ -527,16 +995,39 @@ func (c CfAppsResource) SendBits(d *schema.ResourceData, meta interface{}) error
 	d.Set("remote_sha1", rmtSha1)
 	return nil
 }
-func (c CfAppsResource) IsBitsDiff(d *schema.ResourceData) bool {
+func (c CfAppsResource) IsBitsDiff(d ResourceChangeGetter) bool {
 	return d.HasChange("bits_has_changed") || d.Get("bits_has_changed").(string) != ""
 }
+
+// IsReplacedOnUpdate is true when an update creates a new app and deletes current one, with a blue-green deploy or restage,
+// it follows choices made by createOrUpdate.
+func (c CfAppsResource) IsReplacedOnUpdate(d ResourceChangeGetter) (string, bool) {
+	changed := c.IsBitsDiff(d)
+	for schemaKey := range c.Schema() {
+		changed = changed || d.HasChange(schemaKey)
+	}
+	if !changed || c.IsRoutesUpdate(d) || c.IsScaleUpdate(d) || c.IsRenameUpdate(d) || c.IsScaleAndRename(d) ||
+		c.IsStrategyUpdate(d) || c.IsProcessesUpdate(d) || c.deploymentStrategy(d) != strategyBlueGreen {
+		return "", false
+	}
+	if c.IsBitsDiff(d) {
+		if d.Get("no_blue_green_deploy").(bool) {
+			return "", false
+		}
+		return "replaced by a blue-green deploy, use 'rolling' or 'stop-start' as 'deployment_strategy'", true
+	}
+	if d.Get("no_blue_green_restage").(bool) {
+		return "", false
+	}
+	return "replaced by a blue-green restage, use 'rolling' or 'stop-start' as 'deployment_strategy'", true
+}
 func (c CfAppsResource) updateBitsDiff(d *schema.ResourceData, meta interface{}) error {
 	if d.Get("path").(string) == "" {
 		return nil
 	}
 	client := meta.(cf_client.Client)
 	bm := c.MakeBitsManager(meta)
-	isDiffLocal, sha1Local, err := bm.IsDiff(d.Get("path").(string), d.Get("path_sha1").(string))
+	isDiffLocal, sha1Local, err := bm.IsDiff(client.Context(), d.Get("path").(string), d.Get("path_sha1").(string))
 	if err != nil {
 		return err
 	}
@@ -556,11 +1047,11 @@ func (c CfAppsResource) updateBitsDiff(d *schema.ResourceData, meta interface{})
 func (c CfAppsResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 
-	app, err := client.Finder().GetAppFromCf(d.Id())
+	app, err := client.Finder().GetAppFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
-	currentBindings, err := client.Finder().GetServiceBindingsFromApp(d.Id())
+	currentBindings, err := client.Finder().GetServiceBindingsFromApp(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -603,6 +1094,14 @@ func (c CfAppsResource) Read(d *schema.ResourceData, meta interface{}) error {
 		schemaServices.Add(binding.ServiceInstanceGUID)
 	}
 	d.Set("services", schemaServices)
+	err = c.readProcesses(client, d)
+	if err != nil {
+		return err
+	}
+	err = c.readSidecars(client, d)
+	if err != nil {
+		return err
+	}
 	return c.updateBitsDiff(d, meta)
 }
 func (c CfAppsResource) Update(d *schema.ResourceData, meta interface{}) error {
@@ -614,6 +1113,13 @@ func (c CfAppsResource) Delete(d *schema.ResourceData, meta interface{}) error {
 }
 func (c CfAppsResource) existsWithoutSpaceId(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
+	err := client.Config().RequireAdmin(
+		fmt.Sprintf("finding app %s in every space", d.Get("name").(string)),
+		"set 'space_id' or provider 'default_space'",
+	)
+	if err != nil {
+		return false, err
+	}
 	orgs, err := client.Organizations().ListOrgs(-1)
 	if err != nil {
 		return false, err
@@ -642,7 +1148,7 @@ func (c CfAppsResource) Exists(d *schema.ResourceData, meta interface{}) (bool,
 		return c.existsWithoutSpaceId(d, meta)
 	}
 	if d.Id() != "" {
-		app, err := client.Finder().GetAppFromCf(d.Id())
+		app, err := client.Finder().GetAppFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -658,6 +1164,222 @@ func (c CfAppsResource) Exists(d *schema.ResourceData, meta interface{}) (bool,
 	d.SetId(app.GUID)
 	return true, nil
 }
+func (c CfAppsResource) processesFromSchema(d *schema.ResourceData) ([]cf_client.Process, error) {
+	processes := make([]cf_client.Process, 0)
+	for _, elem := range d.Get("process").(*schema.Set).List() {
+		tfProcess := elem.(map[string]interface{})
+		memory, err := formatters.ToMegabytes(tfProcess["memory"].(string))
+		if err != nil {
+			return processes, err
+		}
+		diskQuota, err := formatters.ToMegabytes(tfProcess["disk_quota"].(string))
+		if err != nil {
+			return processes, err
+		}
+		processes = append(processes, cf_client.Process{
+			Type:                tfProcess["type"].(string),
+			Command:             tfProcess["command"].(string),
+			Instances:           tfProcess["instances"].(int),
+			MemoryInMB:          memory,
+			DiskInMB:            diskQuota,
+			HealthCheckType:     tfProcess["health_check_type"].(string),
+			HealthCheckEndpoint: tfProcess["health_check_http_endpoint"].(string),
+			HealthCheckTimeout:  tfProcess["health_check_timeout"].(int),
+		})
+	}
+	return processes, nil
+}
+
+// updateProcesses sets process types declared by user, only instances of a process type whose command,
+// health check, memory or disk changed are restarted, one by one
+func (c CfAppsResource) updateProcesses(client cf_client.Client, d *schema.ResourceData) error {
+	processes, err := c.processesFromSchema(d)
+	if err != nil {
+		return err
+	}
+	for _, process := range processes {
+		current, err := client.Processes().GetByType(d.Id(), process.Type)
+		if err != nil {
+			return err
+		}
+		if current.GUID == "" {
+			return fmt.Errorf(
+				"Process type %s doesn't exist in app %s, it must be declared in app (e.g.: in a Procfile)",
+				process.Type,
+				d.Get("name").(string),
+			)
+		}
+		err = cf_client.UpdateProcess(client.Context(), client.Processes(), current, process, 5*time.Second)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readProcesses only reads process types declared by user, command is read when user sets it
+func (c CfAppsResource) readProcesses(client cf_client.Client, d *schema.ResourceData) error {
+	currentProcesses := d.Get("process").(*schema.Set)
+	schemaProcesses := schema.NewSet(currentProcesses.F, make([]interface{}, 0))
+	for _, elem := range currentProcesses.List() {
+		tfProcess := elem.(map[string]interface{})
+		process, err := client.Processes().GetByType(d.Id(), tfProcess["type"].(string))
+		if err != nil {
+			return err
+		}
+		if process.GUID == "" {
+			continue
+		}
+		command := ""
+		if tfProcess["command"].(string) != "" {
+			command = process.Command
+		}
+		schemaProcesses.Add(map[string]interface{}{
+			"type":                       process.Type,
+			"command":                    command,
+			"instances":                  process.Instances,
+			"memory":                     formatters.ByteSize(process.MemoryInMB * formatters.MEGABYTE),
+			"disk_quota":                 formatters.ByteSize(process.DiskInMB * formatters.MEGABYTE),
+			"health_check_type":          process.HealthCheckType,
+			"health_check_http_endpoint": process.HealthCheckEndpoint,
+			"health_check_timeout":       process.HealthCheckTimeout,
+		})
+	}
+	d.Set("process", schemaProcesses)
+	return nil
+}
+func (c CfAppsResource) sidecarsFromSchema(d *schema.ResourceData) ([]cf_client.Sidecar, error) {
+	sidecars := make([]cf_client.Sidecar, 0)
+	for _, elem := range d.Get("sidecar").(*schema.Set).List() {
+		tfSidecar := elem.(map[string]interface{})
+		sidecar := cf_client.Sidecar{
+			Name:         tfSidecar["name"].(string),
+			Command:      tfSidecar["command"].(string),
+			ProcessTypes: common.SchemaSetToStringList(tfSidecar["process_types"].(*schema.Set)),
+		}
+		if memory := tfSidecar["memory"].(string); memory != "" {
+			memoryInMB, err := formatters.ToMegabytes(memory)
+			if err != nil {
+				return sidecars, err
+			}
+			sidecar.MemoryInMB = memoryInMB
+		}
+		sidecars = append(sidecars, sidecar)
+	}
+	return sidecars, nil
+}
+
+// updateSidecars creates, updates and deletes sidecars of app to match the ones declared by user,
+// sidecars api is only called when user manages sidecars.
+func (c CfAppsResource) updateSidecars(client cf_client.Client, d *schema.ResourceData) error {
+	if !c.hasSidecars(d) {
+		return nil
+	}
+	sidecars, err := c.sidecarsFromSchema(d)
+	if err != nil {
+		return err
+	}
+	return cf_client.ReconcileSidecars(client.Sidecars(), d.Id(), sidecars)
+}
+
+// hasSidecars is true when sidecars are set in config or in state
+func (c CfAppsResource) hasSidecars(d *schema.ResourceData) bool {
+	return d.HasChange("sidecar") || d.Get("sidecar").(*schema.Set).Len() > 0
+}
+func (c CfAppsResource) readSidecars(client cf_client.Client, d *schema.ResourceData) error {
+	if !c.hasSidecars(d) {
+		return nil
+	}
+	currentSidecars, err := client.Sidecars().List(d.Id())
+	if err != nil {
+		return err
+	}
+	schemaSidecars := schema.NewSet(d.Get("sidecar").(*schema.Set).F, make([]interface{}, 0))
+	for _, sidecar := range currentSidecars {
+		if sidecar.Origin != cf_client.SidecarOriginUser {
+			continue
+		}
+		memory := ""
+		if sidecar.MemoryInMB != 0 {
+			memory = formatters.ByteSize(sidecar.MemoryInMB * formatters.MEGABYTE)
+		}
+		processTypes := make([]interface{}, len(sidecar.ProcessTypes))
+		for i, processType := range sidecar.ProcessTypes {
+			processTypes[i] = processType
+		}
+		schemaSidecars.Add(map[string]interface{}{
+			"name":          sidecar.Name,
+			"command":       sidecar.Command,
+			"process_types": schema.NewSet(schema.HashString, processTypes),
+			"memory":        memory,
+		})
+	}
+	d.Set("sidecar", schemaSidecars)
+	return nil
+}
+func (c CfAppsResource) smokeTestFromSchema(d *schema.ResourceData) (common.SmokeTest, bool) {
+	smokeTests := d.Get("smoke_test").([]interface{})
+	if len(smokeTests) == 0 || smokeTests[0] == nil {
+		return common.SmokeTest{}, false
+	}
+	smokeTest := smokeTests[0].(map[string]interface{})
+	interval, _ := time.ParseDuration(smokeTest["interval"].(string))
+	probe := common.SmokeTest{
+		Path:           smokeTest["path"].(string),
+		ExpectedStatus: smokeTest["status"].(int),
+		Retries:        smokeTest["retries"].(int),
+		Interval:       interval,
+	}
+	if bodyRegex := smokeTest["body_regex"].(string); bodyRegex != "" {
+		probe.BodyRegex = regexp.MustCompile(bodyRegex)
+	}
+	return probe, true
+}
+func (c CfAppsResource) RequiredFeatures(d ResourceGetter) []cf_client.Feature {
+	features := make([]cf_client.Feature, 0)
+	if strategy, ok := d.Get("deployment_strategy").(string); ok && strategy == strategyRolling {
+		features = append(features, cf_client.FeatureDeployments)
+	}
+	if processes, ok := d.Get("process").(*schema.Set); ok && processes.Len() > 0 {
+		features = append(features, cf_client.FeatureProcesses)
+	}
+	if sidecars, ok := d.Get("sidecar").(*schema.Set); ok && sidecars.Len() > 0 {
+		features = append(features, cf_client.FeatureSidecars)
+	}
+	return features
+}
+
+// CheckDiff refuses process blocks on a stopped app, process types only exist once app is staged
+// and they would never be applied. A smoke test which would never be done is also refused.
+func (c CfAppsResource) CheckDiff(d ResourceGetter) error {
+	started, _ := d.Get("started").(bool)
+	processes, ok := d.Get("process").(*schema.Set)
+	if ok && processes.Len() > 0 && !started {
+		return fmt.Errorf("'process' blocks can't be set when 'started' is false, process types only exist once app is staged and started")
+	}
+	return c.checkSmokeTestDiff(d, started)
+}
+
+// checkSmokeTestDiff refuses a smoke test when app is never deployed with a blue-green deployment or restage,
+// new app is only probed during them.
+func (c CfAppsResource) checkSmokeTestDiff(d ResourceGetter, started bool) error {
+	smokeTests, ok := d.Get("smoke_test").([]interface{})
+	if !ok || len(smokeTests) == 0 {
+		return nil
+	}
+	if strategy := c.deploymentStrategy(d); strategy != strategyBlueGreen {
+		return fmt.Errorf("'smoke_test' can't be set when 'deployment_strategy' is '%s', smoke test is only done during a blue-green deployment or restage", strategy)
+	}
+	for _, key := range []string{"no_blue_green_deploy", "no_blue_green_restage"} {
+		if disabled, _ := d.Get(key).(bool); disabled {
+			return fmt.Errorf("'smoke_test' can't be set when '%s' is true, smoke test is only done during a blue-green deployment or restage", key)
+		}
+	}
+	if !started {
+		return fmt.Errorf("'smoke_test' can't be set when 'started' is false, smoke test is only done on running instances")
+	}
+	return nil
+}
 func (c CfAppsResource) Schema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"name": &schema.Schema{
@@ -671,7 +1393,8 @@ func (c CfAppsResource) Schema() map[string]*schema.Schema {
 		},
 		"space_id": &schema.Schema{
 			Type:     schema.TypeString,
-			Required: true,
+			Optional: true,
+			Computed: true,
 			ForceNew: true,
 		},
 		"instances": &schema.Schema{
@@ -763,6 +1486,145 @@ func (c CfAppsResource) Schema() map[string]*schema.Schema {
 			Type:     schema.TypeBool,
 			Optional: true,
 		},
+		"process": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+						ValidateFunc: func(elem interface{}, key string) ([]string, []error) {
+							if elem.(string) == cf_client.ProcessTypeWeb {
+								return nil, []error{fmt.Errorf("%s can't be '%s', web process is set with attributes of the app", key, cf_client.ProcessTypeWeb)}
+							}
+							return nil, nil
+						},
+					},
+					"command": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"instances": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  1,
+					},
+					"memory": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "512M",
+					},
+					"disk_quota": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "1G",
+					},
+					"health_check_type": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "process",
+					},
+					"health_check_http_endpoint": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"health_check_timeout": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"sidecar": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"command": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"process_types": &schema.Schema{
+						Type:     schema.TypeSet,
+						Required: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+						Set:      schema.HashString,
+					},
+					"memory": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"smoke_test": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"path": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "/",
+					},
+					"status": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  http.StatusOK,
+					},
+					"body_regex": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						ValidateFunc: func(elem interface{}, key string) ([]string, []error) {
+							_, err := regexp.Compile(elem.(string))
+							if err != nil {
+								return nil, []error{fmt.Errorf("%s must be a valid regular expression: %s", key, err.Error())}
+							}
+							return nil, nil
+						},
+					},
+					"retries": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  3,
+					},
+					"interval": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "5s",
+						ValidateFunc: func(elem interface{}, key string) ([]string, []error) {
+							_, err := time.ParseDuration(elem.(string))
+							if err != nil {
+								return nil, []error{fmt.Errorf("%s must be a duration (e.g.: 5s, 1m): %s", key, err.Error())}
+							}
+							return nil, nil
+						},
+					},
+				},
+			},
+		},
+		"deployment_strategy": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			ValidateFunc: func(elem interface{}, index string) ([]string, []error) {
+				strategy := elem.(string)
+				if toolbox.HasSliceAnyElements(deploymentStrategies, strategy) {
+					return make([]string, 0), make([]error, 0)
+				}
+				err := fmt.Errorf(
+					"Deployment strategy '%s' is not valid, it must be one of %s",
+					strategy,
+					strings.Join(deploymentStrategies, ", "),
+				)
+				return make([]string, 0), []error{err}
+			},
+		},
 		"path": &schema.Schema{
 			Type:     schema.TypeString,
 			Required: true,
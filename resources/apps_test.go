@@ -0,0 +1,378 @@
+package resources_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/resources"
+
+	"code.cloudfoundry.org/cli/cf/api/apifakes"
+	"code.cloudfoundry.org/cli/cf/models"
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client/fake_cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/rewind"
+	"strings"
+)
+
+var _ = Describe("Apps", func() {
+	Describe("TemporaryRouteHost", func() {
+		It("should give a valid hostname ending with the start of new app guid", func() {
+			Expect(TemporaryRouteHost("My_App.v2", "0A1B2C3D-4e5f-6789-abcd-ef0123456789")).To(Equal("my-app-v2-temporary-0a1b2c3d"))
+		})
+		It("should truncate long app names", func() {
+			host := TemporaryRouteHost(strings.Repeat("a", 43)+"-"+strings.Repeat("b", 20), "0a1b2c3d-4e5f-6789-abcd-ef0123456789")
+			Expect(host).To(Equal(strings.Repeat("a", 43) + "-temporary-0a1b2c3d"))
+			Expect(len(host)).To(BeNumerically("<=", 63))
+		})
+		It("should give different hosts for apps with the same name", func() {
+			Expect(TemporaryRouteHost("my-app", "0a1b2c3d-0000")).ToNot(Equal(TemporaryRouteHost("my-app", "9f8e7d6c-0000")))
+		})
+	})
+	Describe("sidecars", func() {
+		var fakeClient *fake_cf_client.FakeCfClient
+		var resourceData *schema.ResourceData
+		BeforeEach(func() {
+			resource := LoadCfResource(CfAppsResource{})
+			fakeClient = fake_cf_client.NewFakeCfClient()
+			resourceData = resource.Data(&terraform.InstanceState{
+				ID:         "app-guid",
+				Attributes: map[string]string{"name": "my-app"},
+			})
+			fakeClient.FakeSidecars().ListReturns([]cf_client.Sidecar{
+				{GUID: "apm-guid", Name: "apm", Command: "./apm", ProcessTypes: []string{"web"}, Origin: "buildpack"},
+				{GUID: "old-guid", Name: "old", Command: "./old", ProcessTypes: []string{"web"}, Origin: cf_client.SidecarOriginUser},
+			}, nil)
+		})
+		setSidecar := func() {
+			resourceData.Set("sidecar", []interface{}{
+				map[string]interface{}{
+					"name":          "reloader",
+					"command":       "./reload",
+					"process_types": []interface{}{"web"},
+					"memory":        "64M",
+				},
+			})
+		}
+		It("should not call sidecars api when sidecars are neither in config nor in state", func() {
+			Expect(UpdateSidecars(fakeClient.GetClient(), resourceData)).To(Succeed())
+			Expect(ReadSidecars(fakeClient.GetClient(), resourceData)).To(Succeed())
+			Expect(fakeClient.FakeSidecars().ListCallCount()).To(Equal(0))
+			Expect(CfAppsResource{}.RequiredFeatures(resourceData)).ToNot(ContainElement(cf_client.FeatureSidecars))
+		})
+		It("should require sidecars feature when sidecars are set", func() {
+			setSidecar()
+			Expect(CfAppsResource{}.RequiredFeatures(resourceData)).To(ContainElement(cf_client.FeatureSidecars))
+		})
+		It("should reconcile user sidecars without touching buildpack ones", func() {
+			setSidecar()
+			Expect(UpdateSidecars(fakeClient.GetClient(), resourceData)).To(Succeed())
+			Expect(fakeClient.FakeSidecars().CreateCallCount()).To(Equal(1))
+			appGuid, sidecar := fakeClient.FakeSidecars().CreateArgsForCall(0)
+			Expect(appGuid).To(Equal("app-guid"))
+			Expect(sidecar).To(Equal(cf_client.Sidecar{Name: "reloader", Command: "./reload", ProcessTypes: []string{"web"}, MemoryInMB: 64}))
+			Expect(fakeClient.FakeSidecars().DeleteCallCount()).To(Equal(1))
+			Expect(fakeClient.FakeSidecars().DeleteArgsForCall(0)).To(Equal("old-guid"))
+			Expect(fakeClient.FakeSidecars().UpdateCallCount()).To(Equal(0))
+		})
+		It("should only read user sidecars", func() {
+			setSidecar()
+			Expect(ReadSidecars(fakeClient.GetClient(), resourceData)).To(Succeed())
+			sidecars := resourceData.Get("sidecar").(*schema.Set).List()
+			Expect(sidecars).To(HaveLen(1))
+			Expect(sidecars[0].(map[string]interface{})["name"]).To(Equal("old"))
+		})
+	})
+	Describe("blue-green swap", func() {
+		var fakeClient *fake_cf_client.FakeCfClient
+		var fakeRoute *apifakes.FakeRouteRepository
+		var resourceData *schema.ResourceData
+		var actions []rewind.Action
+		const newAppGuid = "0a1b2c3d-4e5f-6789-abcd-ef0123456789"
+		BeforeEach(func() {
+			resource := LoadCfResource(CfAppsResource{})
+			fakeClient = fake_cf_client.NewFakeCfClient()
+			fakeRoute = fakeClient.FakeRoute().(*apifakes.FakeRouteRepository)
+			resourceData = resource.Data(&terraform.InstanceState{
+				ID:         "orig-guid",
+				Attributes: map[string]string{"name": "my-app"},
+			})
+			resourceData.Set("name", "my-app")
+			resourceData.Set("space_id", "space-guid")
+			resourceData.Set("started", true)
+			resourceData.Set("instances", 1)
+			resourceData.Set("routes", []interface{}{"route-guid"})
+
+			fakeClient.FakeFinder().GetRouteFromCfReturns(models.Route{
+				GUID:   "route-guid",
+				Domain: models.DomainFields{GUID: "domain-guid", Name: "example.com"},
+			}, nil)
+			fakeClient.FakeFinder().GetAppFromCfReturns(models.Application{
+				ApplicationFields: models.ApplicationFields{GUID: "orig-guid"},
+				Routes:            []models.RouteSummary{{GUID: "route-guid"}},
+			}, nil)
+			fakeRoute.CreateInSpaceStub = func(host, path, domainGUID, spaceGUID string, port int, randomPort bool) (models.Route, error) {
+				return models.Route{GUID: "temp-route-guid", Host: host}, nil
+			}
+			fakeClient.FakeApplications().GetAppReturns(models.Application{
+				ApplicationFields: models.ApplicationFields{PackageState: "STAGED"},
+			}, nil)
+			fakeClient.FakeAppInstances().GetInstancesReturns([]models.AppInstanceFields{
+				{State: models.InstanceRunning},
+			}, nil)
+
+			actions = BlueGreenSwapActions(resourceData, fakeClient.GetClient(), func() error {
+				resourceData.SetId(newAppGuid)
+				return nil
+			})
+		})
+		appUpdates := func() []string {
+			updates := make([]string, 0)
+			for i := 0; i < fakeClient.FakeApplications().UpdateCallCount(); i++ {
+				guid, params := fakeClient.FakeApplications().UpdateArgsForCall(i)
+				switch {
+				case params.Name != nil:
+					updates = append(updates, guid+" renamed "+*params.Name)
+				case params.State != nil:
+					updates = append(updates, guid+" "+*params.State)
+				}
+			}
+			return updates
+		}
+		routeBindings := func() []string {
+			bindings := make([]string, 0)
+			for i := 0; i < fakeRoute.BindCallCount(); i++ {
+				routeGuid, appGuid := fakeRoute.BindArgsForCall(i)
+				bindings = append(bindings, routeGuid+" to "+appGuid)
+			}
+			return bindings
+		}
+		It("should move routes to new app through a temporary route and delete orig app", func() {
+			err := rewind.Actions{Actions: actions}.Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			host, _, domainGuid, spaceGuid, _, _ := fakeRoute.CreateInSpaceArgsForCall(0)
+			Expect(host).To(Equal("my-app-temporary-0a1b2c3d"))
+			Expect(domainGuid).To(Equal("domain-guid"))
+			Expect(spaceGuid).To(Equal("space-guid"))
+			Expect(routeBindings()).To(Equal([]string{"temp-route-guid to " + newAppGuid, "route-guid to " + newAppGuid}))
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				newAppGuid + " STARTED",
+				"orig-guid STOPPED",
+			}))
+			routeGuid, appGuid := fakeRoute.UnbindArgsForCall(0)
+			Expect(routeGuid + " from " + appGuid).To(Equal("route-guid from orig-guid"))
+			Expect(fakeRoute.DeleteArgsForCall(0)).To(Equal("temp-route-guid"))
+			Expect(fakeClient.FakeApplications().DeleteCallCount()).To(Equal(1))
+			Expect(fakeClient.FakeApplications().DeleteArgsForCall(0)).To(Equal("orig-guid"))
+			Expect(resourceData.Id()).To(Equal(newAppGuid))
+		})
+		It("should delete new app and give back its name to orig app when routes can't be moved", func() {
+			fakeRoute.BindReturnsOnCall(1, errors.New("route can't be bound"))
+
+			err := rewind.Actions{Actions: actions}.Execute()
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeRoute.DeleteCallCount()).To(Equal(1))
+			Expect(fakeRoute.DeleteArgsForCall(0)).To(Equal("temp-route-guid"))
+			Expect(fakeClient.FakeApplications().DeleteCallCount()).To(Equal(1))
+			Expect(fakeClient.FakeApplications().DeleteArgsForCall(0)).To(Equal(newAppGuid))
+			// orig app was never stopped, it is not restarted
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				newAppGuid + " STARTED",
+				"orig-guid renamed my-app",
+			}))
+			Expect(resourceData.Id()).To(Equal("orig-guid"))
+		})
+		It("should restart orig app and give back its routes when it was stopped", func() {
+			fakeRoute.DeleteReturnsOnCall(0, errors.New("route can't be deleted"))
+
+			err := rewind.Actions{Actions: actions}.Execute()
+			Expect(err).To(HaveOccurred())
+
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				newAppGuid + " STARTED",
+				"orig-guid STOPPED",
+				"orig-guid STARTED",
+				"orig-guid renamed my-app",
+			}))
+			Expect(routeBindings()).To(ContainElement("route-guid to orig-guid"))
+			// temporary route deletion is tried again
+			Expect(fakeRoute.DeleteCallCount()).To(Equal(2))
+			Expect(fakeClient.FakeApplications().DeleteArgsForCall(0)).To(Equal(newAppGuid))
+			Expect(resourceData.Id()).To(Equal("orig-guid"))
+		})
+		It("should only give back its name to orig app when new app can't be created", func() {
+			actions = BlueGreenSwapActions(resourceData, fakeClient.GetClient(), func() error {
+				return errors.New("app can't be created")
+			})
+
+			err := rewind.Actions{Actions: actions}.Execute()
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeRoute.CreateInSpaceCallCount()).To(Equal(0))
+			Expect(fakeClient.FakeApplications().DeleteCallCount()).To(Equal(0))
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				"orig-guid renamed my-app",
+			}))
+		})
+		It("should rewind when apply is interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			actions = BlueGreenSwapActions(resourceData, fakeClient.GetClient(), func() error {
+				resourceData.SetId(newAppGuid)
+				cancel()
+				return nil
+			})
+
+			err := rewind.Actions{Actions: actions}.ExecuteContext(ctx)
+			Expect(err).To(MatchError(context.Canceled))
+
+			Expect(fakeRoute.CreateInSpaceCallCount()).To(Equal(0))
+			Expect(fakeClient.FakeApplications().DeleteArgsForCall(0)).To(Equal(newAppGuid))
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				"orig-guid renamed my-app",
+			}))
+			Expect(resourceData.Id()).To(Equal("orig-guid"))
+		})
+		It("should neither start new app nor create a temporary route when app is not started", func() {
+			resourceData.Set("started", false)
+			actions = BlueGreenSwapActions(resourceData, fakeClient.GetClient(), func() error {
+				resourceData.SetId(newAppGuid)
+				return nil
+			})
+
+			err := rewind.Actions{Actions: actions}.Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeRoute.CreateInSpaceCallCount()).To(Equal(0))
+			Expect(fakeRoute.DeleteCallCount()).To(Equal(0))
+			Expect(routeBindings()).To(Equal([]string{"route-guid to " + newAppGuid}))
+			Expect(appUpdates()).To(Equal([]string{
+				"orig-guid renamed my-app-venerable",
+				"orig-guid STOPPED",
+			}))
+			Expect(fakeClient.FakeApplications().DeleteArgsForCall(0)).To(Equal("orig-guid"))
+		})
+	})
+	Describe("rolling deployment", func() {
+		var fakeClient *fake_cf_client.FakeCfClient
+		var app models.Application
+		var cancelCompensated bool
+		BeforeEach(func() {
+			fakeClient = fake_cf_client.NewFakeCfClient()
+			app = models.Application{}
+			app.GUID = "app-guid"
+			app.Name = "my-app"
+			cancelCompensated = false
+			fakeClient.FakeDeployments().CreateReturns(cf_client.Deployment{GUID: "deployment-guid", StatusValue: "ACTIVE"}, nil)
+			fakeClient.FakeDeployments().CancelStub = func(deploymentGuid string) error {
+				cancelCompensated = fakeClient.CompensateCallCount() == 1
+				return nil
+			}
+		})
+		It("should succeed when deployment is finalized as deployed", func() {
+			fakeClient.FakeDeployments().GetReturns(cf_client.Deployment{
+				GUID:         "deployment-guid",
+				StatusValue:  cf_client.DeploymentStatusFinalized,
+				StatusReason: cf_client.DeploymentStateDeployed,
+			}, nil)
+			Expect(DeployDroplet(fakeClient.GetClient(), app, "droplet-guid")).To(Succeed())
+			appGuid, dropletGuid := fakeClient.FakeDeployments().CreateArgsForCall(0)
+			Expect(appGuid).To(Equal("app-guid"))
+			Expect(dropletGuid).To(Equal("droplet-guid"))
+			Expect(fakeClient.FakeDeployments().GetArgsForCall(0)).To(Equal("deployment-guid"))
+			Expect(fakeClient.FakeDeployments().CancelCallCount()).To(Equal(0))
+		})
+		It("should not cancel a deployment finalized without being deployed", func() {
+			fakeClient.FakeDeployments().GetReturns(cf_client.Deployment{
+				GUID:         "deployment-guid",
+				StatusValue:  cf_client.DeploymentStatusFinalized,
+				StatusReason: cf_client.DeploymentStateCanceled,
+			}, nil)
+			err := DeployDroplet(fakeClient.GetClient(), app, "droplet-guid")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("finished with status FINALIZED (CANCELED)"))
+			Expect(fakeClient.FakeDeployments().CancelCallCount()).To(Equal(0))
+		})
+		It("should cancel deployment as a compensation when it fails before being finished", func() {
+			fakeClient.FakeDeployments().GetReturns(cf_client.Deployment{}, errors.New("connection reset"))
+			err := DeployDroplet(fakeClient.GetClient(), app, "droplet-guid")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection reset"))
+			Expect(fakeClient.FakeDeployments().CancelCallCount()).To(Equal(1))
+			Expect(fakeClient.FakeDeployments().CancelArgsForCall(0)).To(Equal("deployment-guid"))
+			Expect(cancelCompensated).To(BeTrue())
+			Expect(fakeClient.FakeLogs().RecentLogsForArgsForCall(0)).To(Equal("app-guid"))
+		})
+		It("should tell when deployment can't be canceled", func() {
+			fakeClient.FakeDeployments().GetReturns(cf_client.Deployment{}, errors.New("connection reset"))
+			fakeClient.FakeDeployments().CancelStub = nil
+			fakeClient.FakeDeployments().CancelReturns(errors.New("unauthorized"))
+			err := DeployDroplet(fakeClient.GetClient(), app, "droplet-guid")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("deployment deployment-guid can't be canceled: unauthorized"))
+		})
+		It("should cancel deployment as a compensation when apply is interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			fakeClient.SetContext(ctx)
+			fakeClient.FakeDeployments().GetStub = func(deploymentGuid string) (cf_client.Deployment, error) {
+				cancel()
+				return cf_client.Deployment{GUID: deploymentGuid, StatusValue: "ACTIVE"}, nil
+			}
+			err := DeployDroplet(fakeClient.GetClient(), app, "droplet-guid")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(context.Canceled.Error()))
+			Expect(fakeClient.FakeDeployments().GetCallCount()).To(Equal(1))
+			Expect(fakeClient.FakeDeployments().CancelArgsForCall(0)).To(Equal("deployment-guid"))
+			Expect(cancelCompensated).To(BeTrue())
+		})
+	})
+	Describe("CheckDiff", func() {
+		var resourceData *schema.ResourceData
+		BeforeEach(func() {
+			resourceData = LoadCfResource(CfAppsResource{}).Data(&terraform.InstanceState{
+				ID:         "app-guid",
+				Attributes: map[string]string{"name": "my-app"},
+			})
+			resourceData.Set("smoke_test", []interface{}{
+				map[string]interface{}{"path": "/health"},
+			})
+		})
+		It("should accept a smoke test done during a blue-green deployment", func() {
+			Expect(CfAppsResource{}.CheckDiff(resourceData)).To(Succeed())
+		})
+		It("should refuse a smoke test with rolling and stop-start strategies", func() {
+			for _, strategy := range []string{"rolling", "stop-start"} {
+				resourceData.Set("deployment_strategy", strategy)
+				err := CfAppsResource{}.CheckDiff(resourceData)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("'deployment_strategy' is '" + strategy + "'"))
+			}
+		})
+		It("should refuse a smoke test when blue-green deployment is disabled", func() {
+			resourceData.Set("no_blue_green_deploy", true)
+			err := CfAppsResource{}.CheckDiff(resourceData)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'no_blue_green_deploy' is true"))
+		})
+		It("should refuse a smoke test when blue-green restage is disabled", func() {
+			resourceData.Set("no_blue_green_restage", true)
+			err := CfAppsResource{}.CheckDiff(resourceData)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'no_blue_green_restage' is true"))
+		})
+		It("should refuse a smoke test on a stopped app", func() {
+			resourceData.Set("started", false)
+			err := CfAppsResource{}.CheckDiff(resourceData)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'started' is false"))
+		})
+	})
+})
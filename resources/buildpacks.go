@@ -49,7 +49,7 @@ func (c CfBuildpackResource) Create(d *schema.ResourceData, meta interface{}) er
 			client.Config().ApiEndpoint,
 			buildpack.Name,
 		)
-		buildpackCf, err = client.Finder().GetBuildpackFromCf(d.Id())
+		buildpackCf, err = client.Finder().GetBuildpackFromCf(client.Context(), d.Id())
 		if err != nil {
 			return err
 		}
@@ -70,7 +70,7 @@ func (c CfBuildpackResource) Create(d *schema.ResourceData, meta interface{}) er
 func (c CfBuildpackResource) Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
-		d, err := client.Finder().GetBuildpackFromCf(d.Id())
+		d, err := client.Finder().GetBuildpackFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -113,7 +113,7 @@ func (c CfBuildpackResource) generateFilename(buildpackPath string) (string, err
 func (c CfBuildpackResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	name := d.Get("name").(string)
-	buildpack, err := client.Finder().GetBuildpackFromCf(d.Id())
+	buildpack, err := client.Finder().GetBuildpackFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -156,7 +156,7 @@ func (c CfBuildpackResource) Update(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	buildpackCf, err := client.Finder().GetBuildpackFromCf(d.Id())
+	buildpackCf, err := client.Finder().GetBuildpackFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
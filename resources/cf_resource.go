@@ -1,6 +1,13 @@
 package resources
 
-import "github.com/hashicorp/terraform/helper/schema"
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"strings"
+	"time"
+)
 
 type CfResource interface {
 	Create(*schema.ResourceData, interface{}) error
@@ -15,28 +22,351 @@ type CfDataSource interface {
 	DataSourceRead(*schema.ResourceData, interface{}) error
 }
 
+// CfFeatureResource is implemented by resources which need features not available on every Cloud Foundry,
+// they are checked at plan time.
+type CfFeatureResource interface {
+	RequiredFeatures(d ResourceGetter) []cf_client.Feature
+}
+
+// CfDiffResource is implemented by resources checking at plan time a change which can't be checked by schema alone
+// (e.g.: attributes which depend on each other).
+type CfDiffResource interface {
+	CheckDiff(d ResourceGetter) error
+}
+
+// ResourceGetter gives access to attributes from a schema.ResourceData or a schema.ResourceDiff
+type ResourceGetter interface {
+	Get(key string) interface{}
+}
+
+// ResourceChangeGetter gives changes of a resource during plan (schema.ResourceDiff) or apply (schema.ResourceData)
+type ResourceChangeGetter interface {
+	ResourceGetter
+	HasChange(key string) bool
+}
+
+// CfReplacingResource is a resource whose update can replace its object on Cloud Foundry by a new one
+// (e.g.: a blue-green deploy of an app), replacing a protected object is refused at plan time.
+type CfReplacingResource interface {
+	IsReplacedOnUpdate(d ResourceChangeGetter) (reason string, replaced bool)
+}
+
 func LoadCfResource(cfResource CfResource) *schema.Resource {
 	return &schema.Resource{
-		Create: cfResource.Create,
-		Read:   cfResource.Read,
-		Update: cfResource.Update,
-		Delete: cfResource.Delete,
-		Exists: cfResource.Exists,
-		Schema: cfResource.Schema(),
+		Create:        refuseInReadOnly(refuseNonAdmin(cfResource, "create", connected(cfResource.Create))),
+		Read:          refuseNonAdmin(cfResource, "read", connected(cfResource.Read)),
+		Update:        refuseInReadOnly(refuseNonAdmin(cfResource, "update", connected(cfResource.Update))),
+		Delete:        refuseInReadOnly(refuseNonAdmin(cfResource, "delete", refuseProtectedDelete(cfResource, connected(cfResource.Delete)))),
+		Exists:        refuseNonAdminExists(cfResource, connectedExists(cfResource.Exists)),
+		Schema:        cfResource.Schema(),
+		CustomizeDiff: customizeDiff(cfResource),
 	}
 }
 func LoadCfResourceNoUpdate(cfResource CfResource) *schema.Resource {
 	return &schema.Resource{
-		Create: cfResource.Create,
-		Read:   cfResource.Read,
-		Delete: cfResource.Delete,
-		Exists: cfResource.Exists,
-		Schema: cfResource.Schema(),
+		Create:        refuseInReadOnly(refuseNonAdmin(cfResource, "create", connected(cfResource.Create))),
+		Read:          refuseNonAdmin(cfResource, "read", connected(cfResource.Read)),
+		Delete:        refuseInReadOnly(refuseNonAdmin(cfResource, "delete", refuseProtectedDelete(cfResource, connected(cfResource.Delete)))),
+		Exists:        refuseNonAdminExists(cfResource, connectedExists(cfResource.Exists)),
+		Schema:        cfResource.Schema(),
+		CustomizeDiff: customizeDiff(cfResource),
 	}
 }
 func LoadCfDataSource(cfDataSource CfDataSource) *schema.Resource {
+	read := cfDataSource.DataSourceRead
+	if featureResource, ok := cfDataSource.(CfFeatureResource); ok {
+		read = func(d *schema.ResourceData, meta interface{}) error {
+			err := requireFeatures(featureResource, d, meta)
+			if err != nil {
+				return err
+			}
+			return cfDataSource.DataSourceRead(d, meta)
+		}
+	}
+	if cfResource, ok := cfDataSource.(CfResource); ok {
+		read = refuseNonAdmin(cfResource, "read", read)
+	}
 	return &schema.Resource{
-		Read:   cfDataSource.DataSourceRead,
+		Read:   connected(read),
 		Schema: cfDataSource.DataSourceSchema(),
 	}
 }
+
+// connected connects client to Cloud Foundry before running f,
+// provider connects only when a resource or a data source needs it.
+func connected(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		err := meta.(cf_client.Client).Connect()
+		if err != nil {
+			return err
+		}
+		return f(d, meta)
+	}
+}
+func connectedExists(f func(*schema.ResourceData, interface{}) (bool, error)) func(*schema.ResourceData, interface{}) (bool, error) {
+	return func(d *schema.ResourceData, meta interface{}) (bool, error) {
+		err := meta.(cf_client.Client).Connect()
+		if err != nil {
+			return false, err
+		}
+		return f(d, meta)
+	}
+}
+
+// refuseInReadOnly stops a change before resource looks for an existing object to adopt,
+// requests are also refused by cf_client but adopting doesn't send any.
+func refuseInReadOnly(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client := meta.(cf_client.Client)
+		if client.Config().ReadOnly {
+			return errors.New("provider is in read only mode, resources can't be changed: set 'read_only' to false to let the provider change Cloud Foundry")
+		}
+		return f(d, meta)
+	}
+}
+
+// adminKind gives what a resource manages when operation on it needs Cloud Foundry admin permissions,
+// an empty kind means that a non admin user can do it.
+func adminKind(cfResource CfResource, operation string) string {
+	// non admin users only see security groups and isolation segments of their spaces and orgs
+	switch cfResource.(type) {
+	case CfSecurityGroupResource:
+		return "security groups"
+	case CfIsolationSegmentsResource:
+		return "isolation segments"
+	case CfIsolationSegmentsEntitlementResource:
+		return "isolation segment entitlements"
+	}
+	if operation == "read" {
+		return ""
+	}
+	switch cfResource.(type) {
+	case CfBuildpackResource:
+		return "buildpacks"
+	case CfFeatureFlagsResource:
+		return "feature flags"
+	case CfEnvVarGroupResource:
+		return "environment variable groups"
+	case CfOrganizationResource:
+		// org managers can rename their org
+		if operation != "update" {
+			return "organizations"
+		}
+	}
+	return ""
+}
+func nonAdminError(meta interface{}, kind, operation string) error {
+	return meta.(cf_client.Client).Config().RequireAdmin(
+		fmt.Sprintf("%s of %s", operation, kind),
+		"remove this resource from your configuration or let an admin manage it",
+	)
+}
+
+// refuseNonAdmin stops an operation which needs admin permissions in non admin mode,
+// it would fail or work on the part of Cloud Foundry visible by user.
+func refuseNonAdmin(cfResource CfResource, operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	kind := adminKind(cfResource, operation)
+	if kind == "" {
+		return f
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		err := nonAdminError(meta, kind, operation)
+		if err != nil {
+			return err
+		}
+		return f(d, meta)
+	}
+}
+func refuseNonAdminExists(cfResource CfResource, f func(*schema.ResourceData, interface{}) (bool, error)) func(*schema.ResourceData, interface{}) (bool, error) {
+	kind := adminKind(cfResource, "read")
+	if kind == "" {
+		return f
+	}
+	return func(d *schema.ResourceData, meta interface{}) (bool, error) {
+		err := nonAdminError(meta, kind, "read")
+		if err != nil {
+			return false, err
+		}
+		return f(d, meta)
+	}
+}
+
+// AddressResources records in journal each change made by resources and gives their errors as cf_client.CCError
+// with their address built from resource type, terraform doesn't give resource name to providers.
+func AddressResources(resourcesMap map[string]*schema.Resource) map[string]*schema.Resource {
+	for resourceType, resource := range resourcesMap {
+		resource.Create = journalChange(resourceType, "create", addressErrors(resourceType, resource.Create))
+		resource.Read = addressErrors(resourceType, resource.Read)
+		if resource.Update != nil {
+			resource.Update = journalChange(resourceType, "update", addressErrors(resourceType, resource.Update))
+		}
+		resource.Delete = journalChange(resourceType, "delete", addressErrors(resourceType, resource.Delete))
+		exists := resource.Exists
+		resource.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			ok, err := exists(d, meta)
+			return ok, addressError(meta, err, resourceAddress(resourceType, d.Id()))
+		}
+	}
+	return resourcesMap
+}
+func addressErrors(resourceType string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		id := d.Id()
+		err := f(d, meta)
+		// id after f is the one of a created resource
+		return addressError(meta, err, resourceAddress(resourceType, d.Id(), id))
+	}
+}
+
+// addressError gives errors from Cloud Controller as cf_client.CCError with address of resource,
+// other errors are given unchanged to keep their type.
+func addressError(meta interface{}, err error, address string) error {
+	if !cf_client.IsCCError(err) {
+		return err
+	}
+	return explainForbidden(meta, cf_client.NewCCError(err, address))
+}
+func explainForbidden(meta interface{}, err error) error {
+	if !meta.(cf_client.Client).Config().NonAdmin {
+		return err
+	}
+	return cf_client.ExplainForbidden(err)
+}
+func journalChange(resourceType, operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		journal := meta.(cf_client.Client).Journal()
+		if journal == nil {
+			return f(d, meta)
+		}
+		record := cf_client.MutationRecord{
+			Time:       time.Now(),
+			Kind:       cf_client.MutationKindResource,
+			Operation:  operation,
+			GUIDBefore: d.Id(),
+		}
+		// requests of resource are recorded with the same address than resource
+		err := meta.(cf_client.Client).ForResource(func() string {
+			return resourceAddress(resourceType, record.GUIDBefore, d.Id())
+		}, func(client cf_client.Client) error {
+			return f(d, client)
+		})
+		record.GUIDAfter = d.Id()
+		if operation == "delete" && err == nil {
+			record.GUIDAfter = ""
+		}
+		record.Resource = resourceAddress(resourceType, record.GUIDBefore, record.GUIDAfter)
+		if err != nil {
+			record.Error = err.Error()
+		}
+		journal.Record(record)
+		return err
+	}
+}
+func resourceAddress(resourceType string, ids ...string) string {
+	for _, id := range ids {
+		if id != "" {
+			return resourceType + "." + id
+		}
+	}
+	return resourceType
+}
+func customizeDiff(cfResource CfResource) schema.CustomizeDiffFunc {
+	featureResource, isFeatureResource := cfResource.(CfFeatureResource)
+	diffResource, isDiffResource := cfResource.(CfDiffResource)
+	kind := protectedKind(cfResource)
+	if !isFeatureResource && !isDiffResource && kind == "" {
+		return nil
+	}
+	forceNewKeys := make([]string, 0)
+	for key, s := range cfResource.Schema() {
+		if s.ForceNew {
+			forceNewKeys = append(forceNewKeys, key)
+		}
+	}
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if isDiffResource {
+			err := diffResource.CheckDiff(d)
+			if err != nil {
+				return err
+			}
+		}
+		if isFeatureResource {
+			err := meta.(cf_client.Client).Connect()
+			if err != nil {
+				return err
+			}
+			err = requireFeatures(featureResource, d, meta)
+			if err != nil {
+				return err
+			}
+		}
+		if kind == "" || d.Id() == "" {
+			return nil
+		}
+		oldName, _ := d.GetChange("name")
+		name, _ := oldName.(string)
+		pattern, protected := meta.(cf_client.Client).Config().Protected.Match(kind, name, d.Id())
+		if !protected {
+			return nil
+		}
+		if d.HasChange("name") {
+			return protectedError(kind, name, pattern, "renamed")
+		}
+		for _, key := range forceNewKeys {
+			if d.HasChange(key) {
+				return protectedError(kind, name, pattern, fmt.Sprintf("replaced (change on '%s' forces a new resource)", key))
+			}
+		}
+		if replacingResource, ok := cfResource.(CfReplacingResource); ok {
+			if reason, replaced := replacingResource.IsReplacedOnUpdate(d); replaced {
+				return protectedError(kind, name, pattern, reason)
+			}
+		}
+		return nil
+	}
+}
+
+// protectedKind gives kind of objects in provider 'protected' block managed by a resource,
+// resources managing other kinds of objects can't be protected.
+func protectedKind(cfResource CfResource) string {
+	switch cfResource.(type) {
+	case CfOrganizationResource:
+		return cf_client.ProtectedOrgs
+	case CfSpaceResource:
+		return cf_client.ProtectedSpaces
+	case CfAppsResource:
+		return cf_client.ProtectedApps
+	case CfServiceResource:
+		return cf_client.ProtectedServices
+	case CfDomainResource:
+		return cf_client.ProtectedDomains
+	}
+	return ""
+}
+func refuseProtectedDelete(cfResource CfResource, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	kind := protectedKind(cfResource)
+	if kind == "" {
+		return f
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		name := d.Get("name").(string)
+		pattern, protected := meta.(cf_client.Client).Config().Protected.Match(kind, name, d.Id())
+		if protected {
+			return protectedError(kind, name, pattern, "deleted")
+		}
+		return f(d, meta)
+	}
+}
+func protectedError(kind, name, pattern, action string) error {
+	return fmt.Errorf(
+		"%s '%s' is protected by pattern '%s' in provider 'protected' block, it can't be %s: remove it from your configuration with 'terraform state rm' or change the provider 'protected' block",
+		strings.TrimSuffix(kind, "s"),
+		name,
+		pattern,
+		action,
+	)
+}
+func requireFeatures(featureResource CfFeatureResource, d ResourceGetter, meta interface{}) error {
+	client := meta.(cf_client.Client)
+	return client.Capabilities().Require(featureResource.RequiredFeatures(d)...)
+}
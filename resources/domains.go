@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/common"
 	"github.com/viant/toolbox"
 	"log"
 )
@@ -45,7 +46,7 @@ func (c CfDomainResource) Create(d *schema.ResourceData, meta interface{}) error
 		c.Exists(d, meta)
 	}
 	domain.GUID = d.Id()
-	domainCf, err := client.Finder().GetDomainFromCf(domain)
+	domainCf, err := client.Finder().GetDomainFromCf(client.Context(), domain)
 	if err != nil {
 		return err
 	}
@@ -62,22 +63,31 @@ func (c CfDomainResource) Create(d *schema.ResourceData, meta interface{}) error
 	if isShared {
 		return nil
 	}
-	currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id())
+	currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id(), orgs)
 	if err != nil {
 		return err
 	}
 	return c.updateSharedToOrg(client, domainCf, currentOrgs, orgs)
 }
-func (c CfDomainResource) getOrgsSharedIdFromCf(client cf_client.Client, domainGuid string) ([]string, error) {
+
+// getOrgsSharedIdFromCf gives orgs where domain is found, in non admin mode only orgs given are checked
+// as user can't see every org.
+func (c CfDomainResource) getOrgsSharedIdFromCf(client cf_client.Client, domainGuid string, orgsIdToCheck []string) ([]string, error) {
 	orgsId := make([]string, 0)
-	orgs, err := client.Organizations().ListOrgs(0)
-	if err != nil {
-		return orgsId, err
+	if !client.Config().NonAdmin {
+		orgs, err := client.Organizations().ListOrgs(0)
+		if err != nil {
+			return orgsId, err
+		}
+		orgsIdToCheck = make([]string, len(orgs))
+		for i, org := range orgs {
+			orgsIdToCheck[i] = org.GUID
+		}
 	}
-	for _, org := range orgs {
-		err := client.Domain().ListDomainsForOrg(org.GUID, func(domainFound models.DomainFields) bool {
+	for _, orgId := range orgsIdToCheck {
+		err := client.Domain().ListDomainsForOrg(orgId, func(domainFound models.DomainFields) bool {
 			if domainFound.GUID == domainGuid {
-				orgsId = append(orgsId, org.GUID)
+				orgsId = append(orgsId, orgId)
 			}
 			return true
 		})
@@ -117,7 +127,7 @@ func (c CfDomainResource) updateSharedToOrg(client cf_client.Client, domain mode
 func (c CfDomainResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	domain := c.resourceObject(d)
-	domainCf, err := client.Finder().GetDomainFromCf(domain)
+	domainCf, err := client.Finder().GetDomainFromCf(client.Context(), domain)
 	if err != nil {
 		return err
 	}
@@ -151,7 +161,7 @@ func (c CfDomainResource) Read(d *schema.ResourceData, meta interface{}) error {
 		d.Set("orgs_shared_id", orgsSharedSchema)
 		return nil
 	}
-	currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id())
+	currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id(), common.SchemaSetToStringList(d.Get("orgs_shared_id").(*schema.Set)))
 	if err != nil {
 		return err
 	}
@@ -166,8 +176,11 @@ func (c CfDomainResource) Read(d *schema.ResourceData, meta interface{}) error {
 
 }
 func (c CfDomainResource) createSharedDomain(client cf_client.Client, domain models.DomainFields, routerName string) error {
+	err := client.Config().RequireAdmin("creating shared domain "+domain.Name, "create a private domain with 'org_owner_id' instead")
+	if err != nil {
+		return err
+	}
 	var routerGuid string
-	var err error
 	if routerName == "" {
 		routerGuid = ""
 	} else {
@@ -207,7 +220,7 @@ func (c CfDomainResource) Exists(d *schema.ResourceData, meta interface{}) (bool
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
 		dOrig := c.resourceObject(d)
-		d, err := client.Finder().GetDomainFromCf(dOrig)
+		d, err := client.Finder().GetDomainFromCf(client.Context(), dOrig)
 		if err != nil {
 			return false, err
 		}
@@ -232,7 +245,7 @@ func (c CfDomainResource) Exists(d *schema.ResourceData, meta interface{}) (bool
 func (c CfDomainResource) Update(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	domain := c.resourceObject(d)
-	domainCf, err := client.Finder().GetDomainFromCf(domain)
+	domainCf, err := client.Finder().GetDomainFromCf(client.Context(), domain)
 	if err != nil {
 		return err
 	}
@@ -251,7 +264,10 @@ func (c CfDomainResource) Update(d *schema.ResourceData, meta interface{}) error
 		for _, org := range orgsSchema.List() {
 			orgs = append(orgs, org.(string))
 		}
-		currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id())
+		// orgs removed from config must be checked too to be unshared
+		oldOrgsSchema, _ := d.GetChange("orgs_shared_id")
+		orgsToCheck := append(common.SchemaSetToStringList(oldOrgsSchema.(*schema.Set)), orgs...)
+		currentOrgs, err := c.getOrgsSharedIdFromCf(client, d.Id(), orgsToCheck)
 		if err != nil {
 			return err
 		}
@@ -270,6 +286,12 @@ func (c CfDomainResource) Delete(d *schema.ResourceData, meta interface{}) error
 	}
 	return nil
 }
+func (c CfDomainResource) RequiredFeatures(d ResourceGetter) []cf_client.Feature {
+	if routerGroup, ok := d.Get("router_group").(string); ok && routerGroup != "" {
+		return []cf_client.Feature{cf_client.FeatureRouterGroups}
+	}
+	return []cf_client.Feature{}
+}
 func (c CfDomainResource) Schema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"name": &schema.Schema{
@@ -0,0 +1,30 @@
+package resources
+
+import (
+	"code.cloudfoundry.org/cli/cf/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/rewind"
+)
+
+var TemporaryRouteHost = temporaryRouteHost
+
+// BlueGreenSwapActions gives steps of a blue-green swap of app in d, new app is created by createNewApp
+func BlueGreenSwapActions(d *schema.ResourceData, meta interface{}, createNewApp func() error) []rewind.Action {
+	return CfAppsResource{}.newBlueGreenSwap(d, meta).actions(createNewApp)
+}
+
+// UpdateSidecars makes sidecars of app in d match its sidecar blocks
+func UpdateSidecars(client cf_client.Client, d *schema.ResourceData) error {
+	return CfAppsResource{}.updateSidecars(client, d)
+}
+
+// ReadSidecars sets sidecar blocks of app in d from cloud controller
+func ReadSidecars(client cf_client.Client, d *schema.ResourceData) error {
+	return CfAppsResource{}.readSidecars(client, d)
+}
+
+// DeployDroplet replaces instances of app a by instances running droplet with a rolling deployment
+func DeployDroplet(client cf_client.Client, a models.Application, dropletGuid string) error {
+	return CfAppsResource{}.deployDroplet(client, a, dropletGuid)
+}
@@ -123,3 +123,6 @@ func (c CfIsolationSegmentsEntitlementResource) Delete(d *schema.ResourceData, m
 	_, err := client.CCv3Client().DeleteIsolationSegmentOrganization(d.Get("segment_id").(string), d.Get("org_id").(string))
 	return err
 }
+func (c CfIsolationSegmentsEntitlementResource) RequiredFeatures(d ResourceGetter) []cf_client.Feature {
+	return []cf_client.Feature{cf_client.FeatureIsolationSegments}
+}
@@ -79,3 +79,6 @@ func (c CfIsolationSegmentSpaceResource) Schema() map[string]*schema.Schema {
 		},
 	}
 }
+func (c CfIsolationSegmentSpaceResource) RequiredFeatures(d ResourceGetter) []cf_client.Feature {
+	return []cf_client.Feature{cf_client.FeatureIsolationSegments}
+}
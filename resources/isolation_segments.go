@@ -216,3 +216,6 @@ func (c CfIsolationSegmentsResource) DataSourceRead(d *schema.ResourceData, meta
 	}
 	return nil
 }
+func (c CfIsolationSegmentsResource) RequiredFeatures(d ResourceGetter) []cf_client.Feature {
+	return []cf_client.Feature{cf_client.FeatureIsolationSegments}
+}
@@ -202,6 +202,11 @@ func (c CfOrganizationsDataSource) DataSourceSchema() map[string]*schema.Schema
 }
 func (c CfOrganizationsDataSource) DataSourceRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
+	// non admin users only see orgs where they are members
+	err := client.Config().RequireAdmin("listing every org", "use a 'cloudfoundry_organization' data source for each org instead")
+	if err != nil {
+		return err
+	}
 	orgs, err := client.Organizations().ListOrgs(0)
 	if err != nil {
 		return err
@@ -0,0 +1,163 @@
+package resources_test
+
+import (
+	. "github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/resources"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client/fake_cf_client"
+)
+
+var _ = Describe("Protected", func() {
+	var fakeClient *fake_cf_client.FakeCfClient
+	BeforeEach(func() {
+		fakeClient = fake_cf_client.NewFakeCfClient()
+		fakeClient.SetProtected(cf_client.ProtectedObjects{
+			cf_client.ProtectedApps:   {"my-app"},
+			cf_client.ProtectedSpaces: {"prod-*"},
+		})
+	})
+	Describe("Delete", func() {
+		It("should refuse to delete a protected space", func() {
+			resource := LoadCfResource(CfSpaceResource{})
+			d := resource.Data(&terraform.InstanceState{
+				ID:         "space-guid",
+				Attributes: map[string]string{"name": "prod-eu"},
+			})
+			err := resource.Delete(d, fakeClient.GetClient())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("space 'prod-eu' is protected by pattern 'prod-*'"))
+			Expect(err.Error()).To(ContainSubstring("it can't be deleted"))
+			Expect(fakeClient.FakeSpaces().DeleteCallCount()).To(Equal(0))
+		})
+		It("should delete a space which is not protected", func() {
+			resource := LoadCfResource(CfSpaceResource{})
+			d := resource.Data(&terraform.InstanceState{
+				ID:         "space-guid",
+				Attributes: map[string]string{"name": "dev"},
+			})
+			Expect(resource.Delete(d, fakeClient.GetClient())).To(Succeed())
+			Expect(fakeClient.FakeSpaces().DeleteCallCount()).To(Equal(1))
+		})
+	})
+	Describe("Diff", func() {
+		var resource *schema.Resource
+		var state *terraform.InstanceState
+		BeforeEach(func() {
+			resource = LoadCfResource(CfAppsResource{})
+			state = &terraform.InstanceState{
+				ID: "app-guid",
+				Attributes: map[string]string{
+					"name":     "my-app",
+					"space_id": "space-guid",
+					"stack_id": "stack-guid",
+					"memory":   "512M",
+				},
+			}
+		})
+		diff := func(raw map[string]interface{}) error {
+			rawConfig, err := config.NewRawConfig(raw)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = resource.Diff(state, terraform.NewResourceConfig(rawConfig), fakeClient.GetClient())
+			return err
+		}
+		It("should refuse to rename a protected app", func() {
+			err := diff(map[string]interface{}{
+				"name":     "new-app",
+				"space_id": "space-guid",
+				"stack_id": "stack-guid",
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("app 'my-app' is protected by pattern 'my-app'"))
+			Expect(err.Error()).To(ContainSubstring("it can't be renamed"))
+		})
+		It("should refuse a change forcing a new protected app", func() {
+			err := diff(map[string]interface{}{
+				"name":     "my-app",
+				"space_id": "space-guid",
+				"stack_id": "other-stack-guid",
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("change on 'stack_id' forces a new resource"))
+		})
+		It("should refuse a blue-green restage of a protected app", func() {
+			err := diff(map[string]interface{}{
+				"name":     "my-app",
+				"space_id": "space-guid",
+				"stack_id": "stack-guid",
+				"memory":   "1G",
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("replaced by a blue-green restage"))
+		})
+		It("should accept a rolling update of a protected app", func() {
+			state.Attributes["deployment_strategy"] = "rolling"
+			Expect(diff(map[string]interface{}{
+				"name":                "my-app",
+				"space_id":            "space-guid",
+				"stack_id":            "stack-guid",
+				"memory":              "1G",
+				"deployment_strategy": "rolling",
+			})).To(Succeed())
+		})
+		It("should accept a stop-start update of a protected app", func() {
+			state.Attributes["deployment_strategy"] = "stop-start"
+			Expect(diff(map[string]interface{}{
+				"name":                "my-app",
+				"space_id":            "space-guid",
+				"stack_id":            "stack-guid",
+				"memory":              "1G",
+				"deployment_strategy": "stop-start",
+			})).To(Succeed())
+		})
+		It("should accept a blue-green restage of an app which is not protected", func() {
+			state.Attributes["name"] = "other-app"
+			Expect(diff(map[string]interface{}{
+				"name":     "other-app",
+				"space_id": "space-guid",
+				"stack_id": "stack-guid",
+				"memory":   "1G",
+			})).To(Succeed())
+		})
+	})
+	Describe("IsReplacedOnUpdate", func() {
+		var d *schema.ResourceData
+		BeforeEach(func() {
+			d = LoadCfResource(CfAppsResource{}).Data(&terraform.InstanceState{
+				ID:         "app-guid",
+				Attributes: map[string]string{"name": "my-app", "memory": "512M"},
+			})
+		})
+		It("should not replace app when nothing changed", func() {
+			_, replaced := CfAppsResource{}.IsReplacedOnUpdate(d)
+			Expect(replaced).To(BeFalse())
+		})
+		It("should replace app on a blue-green deploy of new bits", func() {
+			d.Set("bits_has_changed", "modified")
+			reason, replaced := CfAppsResource{}.IsReplacedOnUpdate(d)
+			Expect(replaced).To(BeTrue())
+			Expect(reason).To(ContainSubstring("blue-green deploy"))
+		})
+		It("should not replace app when blue-green deploy is disabled", func() {
+			d.Set("bits_has_changed", "modified")
+			d.Set("no_blue_green_deploy", true)
+			_, replaced := CfAppsResource{}.IsReplacedOnUpdate(d)
+			Expect(replaced).To(BeFalse())
+		})
+		It("should not replace app when blue-green restage is disabled", func() {
+			d.Set("memory", "1G")
+			d.Set("no_blue_green_restage", true)
+			_, replaced := CfAppsResource{}.IsReplacedOnUpdate(d)
+			Expect(replaced).To(BeFalse())
+		})
+		It("should not replace app on a rename", func() {
+			d.Set("name", "new-app")
+			_, replaced := CfAppsResource{}.IsReplacedOnUpdate(d)
+			Expect(replaced).To(BeFalse())
+		})
+	})
+})
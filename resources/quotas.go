@@ -125,6 +125,8 @@ func (c CfQuotaResource) Create(d *schema.ResourceData, meta interface{}) error
 	_, err = c.Exists(d, meta)
 	return err
 }
+
+// isOrgQuota is true when no org is given, provider 'default_org' is not used as it would turn org quotas into space quotas
 func (c CfQuotaResource) isOrgQuota(d *schema.ResourceData) bool {
 	return d.Get("org_id").(string) == ""
 }
@@ -133,7 +135,7 @@ func (c CfQuotaResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	quotaName := d.Get("name").(string)
 
-	quota, err := client.Finder().GetQuotaFromCf(d.Id(), c.isOrgQuota(d))
+	quota, err := client.Finder().GetQuotaFromCf(client.Context(), d.Id(), c.isOrgQuota(d))
 	if err != nil {
 		return err
 	}
@@ -156,7 +158,7 @@ func (c CfQuotaResource) Update(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
-	quotaCf, err := client.Finder().GetQuotaFromCf(d.Id(), c.isOrgQuota(d))
+	quotaCf, err := client.Finder().GetQuotaFromCf(client.Context(), d.Id(), c.isOrgQuota(d))
 	if err != nil {
 		return err
 	}
@@ -185,7 +187,7 @@ func (c CfQuotaResource) Exists(d *schema.ResourceData, meta interface{}) (bool,
 	client := meta.(cf_client.Client)
 	isOrg := c.isOrgQuota(d)
 	if d.Id() != "" {
-		d, err := client.Finder().GetQuotaFromCf(d.Id(), isOrg)
+		d, err := client.Finder().GetQuotaFromCf(client.Context(), d.Id(), isOrg)
 		if err != nil {
 			return false, err
 		}
@@ -49,9 +49,12 @@ func (c CfRouteResource) generateUri(route models.Route, protocolOverride string
 }
 func (c CfRouteResource) Create(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
+	err := SetDefaultSpaceID(d, meta)
+	if err != nil {
+		return err
+	}
 	route := c.resourceObject(d)
 	var routeCf models.Route
-	var err error
 	if ok, _ := c.Exists(d, meta); ok {
 		log.Printf(
 			"[INFO] skipping creation of route %s/%s because it already exists on your Cloud Foundry",
@@ -81,7 +84,7 @@ func (c CfRouteResource) Create(d *schema.ResourceData, meta interface{}) error
 func (c CfRouteResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	route := c.resourceObject(d)
-	routeCf, err := client.Finder().GetRouteFromCf(d.Id())
+	routeCf, err := client.Finder().GetRouteFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -114,7 +117,7 @@ func (c CfRouteResource) Read(d *schema.ResourceData, meta interface{}) error {
 func (c CfRouteResource) Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
-		d, err := client.Finder().GetRouteFromCf(d.Id())
+		d, err := client.Finder().GetRouteFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -151,14 +154,14 @@ func (c CfRouteResource) updateBinding(client cf_client.Client, currentRoute, wa
 		return nil
 	}
 	if wantedRoute.ServiceInstance.GUID == "" && currentRoute.ServiceInstance.GUID != "" {
-		svc, err := client.Finder().GetServiceFromCf(currentRoute.ServiceInstance.GUID)
+		svc, err := client.Finder().GetServiceFromCf(client.Context(), currentRoute.ServiceInstance.GUID)
 		if err != nil {
 			return err
 		}
 		return client.RouteServiceBinding().Unbind(svc.GUID, currentRoute.GUID, svc.IsUserProvided())
 	}
 
-	svc, err := client.Finder().GetServiceFromCf(wantedRoute.ServiceInstance.GUID)
+	svc, err := client.Finder().GetServiceFromCf(client.Context(), wantedRoute.ServiceInstance.GUID)
 	if err != nil {
 		return err
 	}
@@ -172,7 +175,7 @@ func (c CfRouteResource) updateBinding(client cf_client.Client, currentRoute, wa
 func (c CfRouteResource) Update(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	route := c.resourceObject(d)
-	routeCf, err := client.Finder().GetRouteFromCf(d.Id())
+	routeCf, err := client.Finder().GetRouteFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -191,7 +194,7 @@ func (c CfRouteResource) Update(d *schema.ResourceData, meta interface{}) error
 func (c CfRouteResource) Delete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	if d.Get("service_id").(string) != "" {
-		svc, err := client.Finder().GetServiceFromCf(d.Get("service_id").(string))
+		svc, err := client.Finder().GetServiceFromCf(client.Context(), d.Get("service_id").(string))
 		if err != nil {
 			return err
 		}
@@ -211,7 +214,8 @@ func (c CfRouteResource) Schema() map[string]*schema.Schema {
 		},
 		"space_id": &schema.Schema{
 			Type:     schema.TypeString,
-			Required: true,
+			Optional: true,
+			Computed: true,
 			ForceNew: true,
 		},
 		"hostname": &schema.Schema{
@@ -138,7 +138,7 @@ func (c CfSecurityGroupResource) Create(d *schema.ResourceData, meta interface{}
 func (c CfSecurityGroupResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	secGroupName := d.Get("name").(string)
-	secGroup, err := client.Finder().GetSecGroupFromCf(d.Id())
+	secGroup, err := client.Finder().GetSecGroupFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -173,7 +173,7 @@ func (c CfSecurityGroupResource) Read(d *schema.ResourceData, meta interface{})
 func (c CfSecurityGroupResource) Update(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	secGroup := c.resourceObject(d)
-	secGroupCf, err := client.Finder().GetSecGroupFromCf(d.Id())
+	secGroupCf, err := client.Finder().GetSecGroupFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -275,7 +275,7 @@ func (c CfSecurityGroupResource) existsSecurityGroup(secGroups []models.Security
 func (c CfSecurityGroupResource) Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
-		d, err := client.Finder().GetSecGroupFromCf(d.Id())
+		d, err := client.Finder().GetSecGroupFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -5,7 +5,6 @@ import (
 	"code.cloudfoundry.org/cli/cf/errors"
 	"code.cloudfoundry.org/cli/cf/models"
 	"crypto/sha1"
-	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/hashcode"
@@ -108,10 +107,10 @@ func (c CfServiceBrokerResource) transformServicesAccessToMap(serviceAccess Serv
 		"org_id":  serviceAccess.OrgId,
 	}
 }
-func (c CfServiceBrokerResource) generateCatalogSha1(sb models.ServiceBroker, config cf_client.Config) string {
+func (c CfServiceBrokerResource) generateCatalogSha1(sb models.ServiceBroker, cfClient cf_client.Client) string {
 	tr := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: config.SkipInsecureSSL},
+		Proxy:           cfClient.Proxy(),
+		TLSClientConfig: cfClient.TLSConfig(),
 	}
 	client := &http.Client{
 		Transport: tr,
@@ -273,8 +272,10 @@ func (c CfServiceBrokerResource) isPlanInAllOrgs(client cf_client.Client, planGu
 	}
 	return true, nil
 }
+
+// Create registers a global broker when no space is given, provider 'default_space' is not used as it would turn it into a space-scoped broker
 func (c CfServiceBrokerResource) Create(d *schema.ResourceData, meta interface{}) error {
-	err := c.checkAccess(d)
+	err := c.checkAccess(d, meta)
 	if err != nil {
 		return err
 	}
@@ -302,7 +303,7 @@ func (c CfServiceBrokerResource) Create(d *schema.ResourceData, meta interface{}
 		}
 		c.Exists(d, meta)
 	}
-	d.Set("catalog_sha1", c.generateCatalogSha1(serviceBroker, client.Config()))
+	d.Set("catalog_sha1", c.generateCatalogSha1(serviceBroker, client))
 	d.Set("previous_password", d.Get("password"))
 	if c.isSpaceScoped(d) {
 		return nil
@@ -311,12 +312,28 @@ func (c CfServiceBrokerResource) Create(d *schema.ResourceData, meta interface{}
 	servicesAccess := c.serviceAccessObjects(d)
 	return c.updateServicesAccess(client, serviceBrokerCf, servicesAccess)
 }
-func (c CfServiceBrokerResource) checkAccess(d *schema.ResourceData) error {
-	if c.isSpaceScoped(d) || d.Get("service_access").(*schema.Set).Len() > 0 {
+func (c CfServiceBrokerResource) checkAccess(d *schema.ResourceData, meta interface{}) error {
+	if c.isSpaceScoped(d) {
+		return nil
+	}
+	err := c.requireAdminForServiceAccess(d, meta)
+	if err != nil {
+		return err
+	}
+	if d.Get("service_access").(*schema.Set).Len() > 0 {
 		return nil
 	}
 	return fmt.Errorf(`"service_access" or "space_id" must be set`)
 }
+
+// requireAdminForServiceAccess refuses brokers which are not space scoped in non admin mode,
+// service plan visibilities can only be managed by an admin.
+func (c CfServiceBrokerResource) requireAdminForServiceAccess(d *schema.ResourceData, meta interface{}) error {
+	return meta.(cf_client.Client).Config().RequireAdmin(
+		fmt.Sprintf("managing 'service_access' of service broker %s", d.Get("name").(string)),
+		"set 'space_id' to register a space scoped service broker",
+	)
+}
 func (c CfServiceBrokerResource) isSpaceScoped(d *schema.ResourceData) bool {
 	return d.Get("space_id").(string) != ""
 }
@@ -562,7 +579,7 @@ func (c CfServiceBrokerResource) Read(d *schema.ResourceData, meta interface{})
 	d.Set("password", d.Get("previous_password"))
 	currentSha1 := d.Get("catalog_sha1").(string)
 	brokerCf.Password = broker.Password
-	remoteSha1 := c.generateCatalogSha1(brokerCf, client.Config())
+	remoteSha1 := c.generateCatalogSha1(brokerCf, client)
 	if currentSha1 == remoteSha1 {
 		d.Set("catalog_has_changed", "")
 	} else {
@@ -572,6 +589,10 @@ func (c CfServiceBrokerResource) Read(d *schema.ResourceData, meta interface{})
 	if c.isSpaceScoped(d) {
 		return nil
 	}
+	err = c.requireAdminForServiceAccess(d, meta)
+	if err != nil {
+		return err
+	}
 	servicesAccess, err := c.retrieveServicesAccessFromBroker(client, brokerCf)
 
 	serviceAccessSchema := schema.NewSet(d.Get("service_access").(*schema.Set).F, make([]interface{}, 0))
@@ -582,7 +603,7 @@ func (c CfServiceBrokerResource) Read(d *schema.ResourceData, meta interface{})
 	return nil
 }
 func (c CfServiceBrokerResource) Update(d *schema.ResourceData, meta interface{}) error {
-	err := c.checkAccess(d)
+	err := c.checkAccess(d, meta)
 	if err != nil {
 		return err
 	}
@@ -610,7 +631,7 @@ func (c CfServiceBrokerResource) Update(d *schema.ResourceData, meta interface{}
 	brokerCf.Password = broker.Password
 	d.Set("previous_password", d.Get("password"))
 	currentCatalogSha1 := d.Get("catalog_sha1")
-	d.Set("catalog_sha1", c.generateCatalogSha1(brokerCf, client.Config()))
+	d.Set("catalog_sha1", c.generateCatalogSha1(brokerCf, client))
 	if broker.Name != brokerCf.Name ||
 		broker.URL != brokerCf.URL ||
 		broker.Username != brokerCf.Username ||
@@ -39,12 +39,15 @@ func (c CfServiceResource) findPlanGuid(client cf_client.Client, service, plan s
 }
 func (c CfServiceResource) Create(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
+	err := SetDefaultSpaceID(d, meta)
+	if err != nil {
+		return err
+	}
 	svc := c.resourceObject(d)
 	isUserProvided := d.Get("user_provided").(bool)
 	client.Gateways().Config.SetSpaceFields(models.SpaceFields{
 		GUID: d.Get("space_id").(string),
 	})
-	var err error
 	var planGuid string
 	if ok, _ := c.Exists(d, meta); ok {
 		log.Printf(
@@ -73,7 +76,7 @@ func (c CfServiceResource) Create(d *schema.ResourceData, meta interface{}) erro
 		}
 		c.Exists(d, meta)
 	}
-	svcCf, err := client.Finder().GetServiceFromCf(d.Id())
+	svcCf, err := client.Finder().GetServiceFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -117,7 +120,7 @@ func (c CfServiceResource) Read(d *schema.ResourceData, meta interface{}) error
 		GUID: d.Get("space_id").(string),
 	})
 	svc := c.resourceObject(d)
-	svcCf, err := client.Finder().GetServiceFromCf(d.Id())
+	svcCf, err := client.Finder().GetServiceFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -147,7 +150,7 @@ func (c CfServiceResource) Read(d *schema.ResourceData, meta interface{}) error
 func (c CfServiceResource) Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
-		d, err := client.Finder().GetServiceFromCf(d.Id())
+		d, err := client.Finder().GetServiceFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -174,7 +177,7 @@ func (c CfServiceResource) Update(d *schema.ResourceData, meta interface{}) erro
 		GUID: d.Get("space_id").(string),
 	})
 	svc := c.resourceObject(d)
-	svcCf, err := client.Finder().GetServiceFromCf(d.Id())
+	svcCf, err := client.Finder().GetServiceFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -227,7 +230,8 @@ func (c CfServiceResource) Schema() map[string]*schema.Schema {
 		},
 		"space_id": &schema.Schema{
 			Type:     schema.TypeString,
-			Required: true,
+			Optional: true,
+			Computed: true,
 		},
 		"service": &schema.Schema{
 			Type:     schema.TypeString,
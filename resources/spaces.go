@@ -49,8 +49,11 @@ func (c CfSpaceResource) extractSecGroups(d *schema.ResourceData) []models.Secur
 
 func (c CfSpaceResource) Create(d *schema.ResourceData, meta interface{}) error {
 	var spaceCf models.Space
-	var err error
 	client := meta.(cf_client.Client)
+	err := SetDefaultOrgID(d, meta)
+	if err != nil {
+		return err
+	}
 	space := c.resourceObject(d)
 
 	if ok, _ := c.Exists(d, meta); ok {
@@ -59,7 +62,7 @@ func (c CfSpaceResource) Create(d *schema.ResourceData, meta interface{}) error
 			client.Config().ApiEndpoint,
 			space.Name,
 		)
-		spaceCf, err = client.Finder().GetSpaceFromCf(d.Id())
+		spaceCf, err = client.Finder().GetSpaceFromCf(client.Context(), d.Id())
 	} else {
 		spaceCf, err = client.Spaces().Create(space.Name, space.Organization.GUID, space.SpaceQuotaGUID)
 	}
@@ -102,7 +105,7 @@ func (c CfSpaceResource) updateSecGroups(client cf_client.Client, secGroupFrom,
 func (c CfSpaceResource) filterSecGroup(client cf_client.Client, secGroupFields, secGroupFieldsFromTf []models.SecurityGroupFields) []models.SecurityGroupFields {
 	secGroupsFiltered := make([]models.SecurityGroupFields, 0)
 	for _, secGroupField := range secGroupFields {
-		secGroup, _ := client.Finder().GetSecGroupFromCf(secGroupField.GUID)
+		secGroup, _ := client.Finder().GetSecGroupFromCf(client.Context(), secGroupField.GUID)
 		if secGroup.GUID == "" || len(secGroup.Spaces) == 0 {
 			continue
 		}
@@ -125,7 +128,7 @@ func (c CfSpaceResource) filterSecGroupByTerraformExist(client cf_client.Client,
 func (c CfSpaceResource) Read(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	name := d.Get("name").(string)
-	space, err := client.Finder().GetSpaceFromCf(d.Id())
+	space, err := client.Finder().GetSpaceFromCf(client.Context(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -152,7 +155,7 @@ func (c CfSpaceResource) Read(d *schema.ResourceData, meta interface{}) error {
 func (c CfSpaceResource) Update(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(cf_client.Client)
 	space := c.resourceObject(d)
-	spaceCf, err := client.Finder().GetSpaceFromCf(space.GUID)
+	spaceCf, err := client.Finder().GetSpaceFromCf(client.Context(), space.GUID)
 	if err != nil {
 		return err
 	}
@@ -205,7 +208,7 @@ func (c CfSpaceResource) Delete(d *schema.ResourceData, meta interface{}) error
 func (c CfSpaceResource) Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(cf_client.Client)
 	if d.Id() != "" {
-		d, err := client.Finder().GetSpaceFromCf(d.Id())
+		d, err := client.Finder().GetSpaceFromCf(client.Context(), d.Id())
 		if err != nil {
 			return false, err
 		}
@@ -240,7 +243,8 @@ func (c CfSpaceResource) Schema() map[string]*schema.Schema {
 		},
 		"org_id": &schema.Schema{
 			Type:     schema.TypeString,
-			Required: true,
+			Optional: true,
+			Computed: true,
 		},
 		"allow_ssh": &schema.Schema{
 			Type:     schema.TypeBool,
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/cf_client"
 	"github.com/viant/toolbox"
 	"strings"
 )
@@ -83,6 +84,32 @@ func CreateDataSourceReadFuncWithReq(resource CfResource, required ...string) fu
 		return CreateDataSourceReadFunc(resource)(d, meta)
 	}
 }
+
+// SetDefaultSpaceID sets 'space_id' to the space given in provider 'default_space' when it is not set
+func SetDefaultSpaceID(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("space_id").(string) != "" {
+		return nil
+	}
+	space := meta.(cf_client.Client).DefaultSpace()
+	if space.GUID == "" {
+		return fmt.Errorf("'space_id' must be set when provider 'default_space' is not")
+	}
+	d.Set("space_id", space.GUID)
+	return nil
+}
+
+// SetDefaultOrgID sets 'org_id' to the org given in provider 'default_org' when it is not set
+func SetDefaultOrgID(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("org_id").(string) != "" {
+		return nil
+	}
+	org := meta.(cf_client.Client).DefaultOrg()
+	if org.GUID == "" {
+		return fmt.Errorf("'org_id' must be set when provider 'default_org' is not")
+	}
+	d.Set("org_id", org.GUID)
+	return nil
+}
 func ConvertParamsToMap(params string) map[string]interface{} {
 	if params == "" {
 		return make(map[string]interface{})
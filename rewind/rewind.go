@@ -203,7 +203,10 @@ See the License for the specific language governing permissions and
 limitations under the License.*/
 package rewind
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type Actions struct {
 	Actions []Action
@@ -212,8 +215,17 @@ type Actions struct {
 }
 
 func (actions Actions) Execute() error {
+	return actions.ExecuteContext(context.Background())
+}
+
+// ExecuteContext runs actions until context is done, an action which was not run
+// because of context is rewound as if it had failed.
+func (actions Actions) ExecuteContext(ctx context.Context) error {
 	for _, action := range actions.Actions {
-		err := action.Forward()
+		err := ctx.Err()
+		if err == nil {
+			err = action.Forward()
+		}
 		if err != nil {
 			if action.ReversePrevious == nil {
 				return err
@@ -1,12 +1,13 @@
 package rewind_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
-	"github.com/contraband/autopilot/rewind"
+	"github.com/orange-cloudfoundry/terraform-provider-cloudfoundry/rewind"
 )
 
 var _ = Describe("Rewind", func() {
@@ -164,4 +165,40 @@ var _ = Describe("Rewind", func() {
 		Expect(secondReverseRun).To(BeTrue())
 		Expect(thirdRun).To(BeFalse())
 	})
+
+	It("stops and runs the rewind of the next action when context is done", func() {
+		firstRun := false
+		secondRun := false
+		secondReverseRun := false
+
+		ctx, cancel := context.WithCancel(context.Background())
+		actions := rewind.Actions{
+			Actions: []rewind.Action{
+				{
+					Forward: func() error {
+						firstRun = true
+						cancel()
+						return nil
+					},
+				},
+				{
+					Forward: func() error {
+						secondRun = true
+						return nil
+					},
+					ReversePrevious: func() error {
+						secondReverseRun = true
+						return nil
+					},
+				},
+			},
+		}
+
+		err := actions.ExecuteContext(ctx)
+		Expect(err).To(MatchError(context.Canceled))
+
+		Expect(firstRun).To(BeTrue())
+		Expect(secondRun).To(BeFalse())
+		Expect(secondReverseRun).To(BeTrue())
+	})
 })